@@ -0,0 +1,322 @@
+package ldap
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// accessRights maps the access right names to their access mask. The generic
+// rights are given with the specific rights Active Directory stores them as.
+var accessRights = map[string]uint32{
+	"generic_all":     rightFullControl,
+	"generic_read":    rightReadControl | rightDSListChildren | rightDSReadProperty | rightDSListObject,
+	"generic_write":   rightReadControl | rightDSSelf | rightDSWriteProperty,
+	"generic_execute": rightReadControl | rightDSListChildren,
+	"create_child":    rightDSCreateChild,
+	"delete_child":    rightDSDeleteChild,
+	"list_children":   rightDSListChildren,
+	"self_write":      rightDSSelf,
+	"read_property":   rightDSReadProperty,
+	"write_property":  rightDSWriteProperty,
+	"delete_tree":     rightDSDeleteTree,
+	"list_object":     rightDSListObject,
+	"control_access":  rightDSControlAccess,
+	"delete":          rightDelete,
+	"read_control":    rightReadControl,
+	"write_dac":       rightWriteDAC,
+	"write_owner":     rightWriteOwner,
+}
+
+// genericAccessRights are the access rights names made of several rights,
+// from the widest to the narrowest
+var genericAccessRights = []string{"generic_all", "generic_read", "generic_write", "generic_execute"}
+
+// aceInheritances maps the inheritance names, like the .NET
+// ActiveDirectorySecurityInheritance ones, to their ACE flags
+var aceInheritances = map[string]byte{
+	"none":              0,
+	"all":               aceFlagContainerInherit,
+	"descendents":       aceFlagContainerInherit | aceFlagInheritOnly,
+	"self_and_children": aceFlagContainerInherit | aceFlagNoPropagate,
+	"children":          aceFlagContainerInherit | aceFlagNoPropagate | aceFlagInheritOnly,
+}
+
+// aceInheritanceFlags are the ACE flags describing the inheritance
+const aceInheritanceFlags = aceFlagObjectInherit | aceFlagContainerInherit | aceFlagNoPropagate | aceFlagInheritOnly
+
+// accessRightsNames returns the sorted access right names of the keys of accessRights
+func accessRightsNames() []string {
+	names := []string{}
+	for name := range accessRights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// aceInheritanceNames returns the sorted inheritance names of the keys of aceInheritances
+func aceInheritanceNames() []string {
+	names := []string{}
+	for name := range aceInheritances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// expandAccessRights converts access right names to an access mask
+func expandAccessRights(names []string) uint32 {
+	mask := uint32(0)
+	for _, name := range names {
+		mask |= accessRights[name]
+	}
+
+	return mask
+}
+
+// flattenAccessRights converts an access mask to access right names, using
+// the generic rights names when all their rights are set
+func flattenAccessRights(mask uint32) []string {
+	names := []string{}
+	covered := uint32(0)
+
+	for _, name := range genericAccessRights {
+		rights := accessRights[name]
+		if mask&rights == rights && rights&^covered != 0 {
+			names = append(names, name)
+			covered |= rights
+		}
+	}
+
+	for _, name := range accessRightsNames() {
+		rights := accessRights[name]
+		if mask&rights == rights && rights&^covered != 0 {
+			names = append(names, name)
+			covered |= rights
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// flattenACEInheritance returns the inheritance name of the ACE flags,
+// or an empty string if the flags match none
+func flattenACEInheritance(flags byte) string {
+	for name, inheritanceFlags := range aceInheritances {
+		if flags&aceInheritanceFlags == inheritanceFlags {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// isGUID returns true if the value is a string GUID
+func isGUID(value string) bool {
+	_, err := encodeGUID(value)
+	return err == nil && len(strings.Trim(value, "{}")) == 36
+}
+
+// resolveTrusteeSID returns the SID of a trustee given by SID or DN
+func (c *providerClient) resolveTrusteeSID(trustee string) (string, error) {
+	if strings.HasPrefix(strings.ToUpper(trustee), "S-1-") {
+		if _, err := encodeSID(trustee); err != nil {
+			return "", err
+		}
+		return strings.ToUpper(trustee), nil
+	}
+
+	sids, err := c.readSIDs([]string{trustee})
+	if err != nil {
+		return "", err
+	}
+
+	return sids[0], nil
+}
+
+// resolveObjectTypeGUID returns the GUID of an object type given by GUID, by schema
+// class or attribute lDAPDisplayName, or by extended right, validated write or property
+// set name or display name
+func (c *providerClient) resolveObjectTypeGUID(objectType string) (string, error) {
+	if isGUID(objectType) {
+		return strings.ToLower(strings.Trim(objectType, "{}")), nil
+	}
+
+	rootDSE, err := c.readRootDSE([]string{"schemaNamingContext", "configurationNamingContext"})
+	if err != nil {
+		return "", err
+	}
+
+	filter := fmt.Sprintf("(lDAPDisplayName=%s)", ldap.EscapeFilter(objectType))
+	entries, err := c.search(rootDSE.GetAttributeValue("schemaNamingContext"), ldap.ScopeSingleLevel, filter, []string{"schemaIDGUID"})
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 {
+		return decodeGUID(entries[0].GetRawAttributeValue("schemaIDGUID"))
+	}
+
+	filter = fmt.Sprintf("(&(objectClass=controlAccessRight)(|(name=%s)(displayName=%s)))", ldap.EscapeFilter(objectType), ldap.EscapeFilter(objectType))
+	entries, err = c.search("CN=Extended-Rights,"+rootDSE.GetAttributeValue("configurationNamingContext"), ldap.ScopeSingleLevel, filter, []string{"rightsGuid"})
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 {
+		return strings.ToLower(entries[0].GetAttributeValue("rightsGuid")), nil
+	}
+	if len(entries) > 1 {
+		return "", fmt.Errorf("several extended rights named %q, use its GUID or name", objectType)
+	}
+
+	return "", fmt.Errorf("object type %q not found in the schema nor in the extended rights", objectType)
+}
+
+// resolveObjectTypeNames returns the names of the schema classes and attributes,
+// and of the extended rights, with the given GUIDs, by lowercase GUID
+func (c *providerClient) resolveObjectTypeNames(guids []string) (map[string]string, error) {
+	names := map[string]string{}
+	if len(guids) == 0 {
+		return names, nil
+	}
+
+	rootDSE, err := c.readRootDSE([]string{"schemaNamingContext", "configurationNamingContext"})
+	if err != nil {
+		return nil, err
+	}
+
+	schemaFilter := "(|"
+	rightsFilter := "(&(objectClass=controlAccessRight)(|"
+	for _, guid := range guids {
+		binaryGUID, err := encodeGUID(guid)
+		if err != nil {
+			return nil, err
+		}
+		schemaFilter += fmt.Sprintf("(schemaIDGUID=%s)", escapeBinaryFilter(binaryGUID))
+		rightsFilter += fmt.Sprintf("(rightsGuid=%s)", ldap.EscapeFilter(guid))
+	}
+	schemaFilter += ")"
+	rightsFilter += "))"
+
+	entries, err := c.search(rootDSE.GetAttributeValue("schemaNamingContext"), ldap.ScopeSingleLevel, schemaFilter, []string{"lDAPDisplayName", "schemaIDGUID"})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		guid, err := decodeGUID(entry.GetRawAttributeValue("schemaIDGUID"))
+		if err != nil {
+			return nil, err
+		}
+		names[guid] = entry.GetAttributeValue("lDAPDisplayName")
+	}
+
+	entries, err = c.search("CN=Extended-Rights,"+rootDSE.GetAttributeValue("configurationNamingContext"), ldap.ScopeSingleLevel, rightsFilter, []string{"name", "rightsGuid"})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		names[strings.ToLower(entry.GetAttributeValue("rightsGuid"))] = entry.GetAttributeValue("name")
+	}
+
+	return names, nil
+}
+
+// sddl returns the SDDL string of the ACE, e.g. (OA;CI;0x100;<guid>;<guid>;S-1-5-21-...)
+func (a ace) sddl() string {
+	aceType := map[byte]string{
+		aceTypeAccessAllowed:       "A",
+		aceTypeAccessDenied:        "D",
+		aceTypeSystemAudit:         "AU",
+		aceTypeAccessAllowedObject: "OA",
+		aceTypeAccessDeniedObject:  "OD",
+		aceTypeSystemAuditObject:   "OU",
+	}[a.Type]
+
+	flags := ""
+	for _, flag := range []struct {
+		value byte
+		name  string
+	}{
+		{aceFlagObjectInherit, "OI"},
+		{aceFlagContainerInherit, "CI"},
+		{aceFlagNoPropagate, "NP"},
+		{aceFlagInheritOnly, "IO"},
+		{aceFlagInherited, "ID"},
+	} {
+		if a.Flags&flag.value != 0 {
+			flags += flag.name
+		}
+	}
+
+	return fmt.Sprintf("(%s;%s;0x%x;%s;%s;%s)", aceType, flags, a.Mask, a.ObjectType, a.InheritedObjectType, a.SID)
+}
+
+// parseACESDDL parses the SDDL string of an ACE as returned by sddl. Only
+// the access allowed and denied ACE types, with or without object types, and
+// a hexadecimal access mask are supported.
+func parseACESDDL(value string) (ace, error) {
+	fields := strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "("), ")"), ";")
+	if len(fields) != 6 {
+		return ace{}, fmt.Errorf("invalid ACE SDDL %q, expected (type;flags;mask;object_type;inherited_object_type;sid)", value)
+	}
+
+	entry := ace{
+		ObjectType:          strings.ToLower(fields[3]),
+		InheritedObjectType: strings.ToLower(fields[4]),
+		SID:                 strings.ToUpper(fields[5]),
+	}
+
+	aceType, ok := map[string]byte{
+		"A":  aceTypeAccessAllowed,
+		"D":  aceTypeAccessDenied,
+		"OA": aceTypeAccessAllowedObject,
+		"OD": aceTypeAccessDeniedObject,
+	}[strings.ToUpper(fields[0])]
+	if !ok {
+		return ace{}, fmt.Errorf("unsupported ACE type %q in %q", fields[0], value)
+	}
+	entry.Type = aceType
+
+	flags := strings.ToUpper(fields[1])
+	if len(flags)%2 != 0 {
+		return ace{}, fmt.Errorf("invalid ACE flags %q in %q", fields[1], value)
+	}
+	for i := 0; i < len(flags); i += 2 {
+		flag, ok := map[string]byte{
+			"OI": aceFlagObjectInherit,
+			"CI": aceFlagContainerInherit,
+			"NP": aceFlagNoPropagate,
+			"IO": aceFlagInheritOnly,
+			"ID": aceFlagInherited,
+		}[flags[i:i+2]]
+		if !ok {
+			return ace{}, fmt.Errorf("unsupported ACE flag %q in %q", flags[i:i+2], value)
+		}
+		entry.Flags |= flag
+	}
+
+	mask, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(fields[2]), "0x"), 16, 32)
+	if err != nil {
+		return ace{}, fmt.Errorf("invalid ACE access mask %q in %q: %w", fields[2], value, err)
+	}
+	entry.Mask = uint32(mask)
+
+	for _, guid := range []string{entry.ObjectType, entry.InheritedObjectType} {
+		if guid != "" && !isGUID(guid) {
+			return ace{}, fmt.Errorf("invalid object type GUID %q in %q", guid, value)
+		}
+	}
+
+	if _, err := encodeSID(entry.SID); err != nil {
+		return ace{}, fmt.Errorf("invalid SID %q in %q: %w", entry.SID, value, err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,123 @@
+package ldap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fileTimeEpochOffset is the number of 100ns intervals between
+// the FILETIME epoch (1601-01-01) and the Unix epoch (1970-01-01)
+const fileTimeEpochOffset = 116444736000000000
+
+// fileTimeNever is the FILETIME value used by Active Directory for "never"
+const fileTimeNever = 0x7FFFFFFFFFFFFFFF
+
+// fileTimeToRFC3339 converts an Active Directory FILETIME (100ns intervals
+// since 1601-01-01 UTC) to RFC 3339. An empty string is returned for empty,
+// 0 and "never" values.
+func fileTimeToRFC3339(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	fileTime, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid FILETIME %q: %w", value, err)
+	}
+
+	if fileTime <= 0 || fileTime == fileTimeNever {
+		return "", nil
+	}
+
+	// Converting to nanoseconds would overflow int64 after year 2262
+	intervals := fileTime - fileTimeEpochOffset
+
+	return time.Unix(intervals/1e7, (intervals%1e7)*100).UTC().Format(time.RFC3339), nil
+}
+
+// generalizedTimeToRFC3339 converts an Active Directory GeneralizedTime
+// (e.g. 20230102150405.0Z) to RFC 3339
+func generalizedTimeToRFC3339(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	// Active Directory always returns UTC values with an optional fraction
+	trimmed := strings.TrimSuffix(value, "Z")
+	if i := strings.IndexAny(trimmed, ".,"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	t, err := time.ParseInLocation("20060102150405", trimmed, time.UTC)
+	if err != nil {
+		return "", fmt.Errorf("invalid GeneralizedTime %q: %w", value, err)
+	}
+
+	return t.Format(time.RFC3339), nil
+}
+
+// adIntervalNever is the interval value used by Active Directory for "never"
+const adIntervalNever = -0x8000000000000000
+
+// adIntervalToDuration converts an Active Directory interval (negative number
+// of 100ns intervals, as used by maxPwdAge or lockoutDuration) to a Go
+// duration string. An empty string is returned for empty and "never" values.
+func adIntervalToDuration(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	interval, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid interval %q: %w", value, err)
+	}
+
+	if interval == adIntervalNever {
+		return "", nil
+	}
+	if interval < 0 {
+		interval = -interval
+	}
+
+	return (time.Duration(interval) * 100).String(), nil
+}
+
+// passwordPolicyNeverArguments are the duration arguments of the password policies
+// for which the zero duration means "never", stored as the never interval
+var passwordPolicyNeverArguments = map[string]bool{
+	"max_password_age": true,
+	"lockout_duration": true,
+}
+
+// flattenPasswordPolicyDuration converts the Active Directory interval of a password
+// policy duration argument to a Go duration string, "never" being returned as the
+// zero duration for the arguments of passwordPolicyNeverArguments
+func flattenPasswordPolicyDuration(argument, value string) (string, error) {
+	duration, err := adIntervalToDuration(value)
+	if err != nil {
+		return "", err
+	}
+
+	if duration == "" && value != "" && passwordPolicyNeverArguments[argument] {
+		return "0s", nil
+	}
+
+	return duration, nil
+}
+
+// durationToADInterval converts a Go duration string to an Active Directory
+// interval (negative number of 100ns intervals)
+func durationToADInterval(value string) (string, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+
+	if duration < 0 {
+		return "", fmt.Errorf("invalid duration %q: must not be negative", value)
+	}
+
+	return strconv.FormatInt(-int64(duration/100), 10), nil
+}
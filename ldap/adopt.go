@@ -0,0 +1,50 @@
+package ldap
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// adoptExistingEntry returns true if adopt_existing is enabled and an entry
+// with the given objectClass already exists at the DN. An error is returned
+// if the existing entry has another objectClass.
+func adoptExistingEntry(client *providerClient, d *schema.ResourceData, dn, objectClass string) (bool, error) {
+	if !d.Get("adopt_existing").(bool) {
+		return false, nil
+	}
+
+	entry, err := client.readEntry(dn, []string{"objectClass"})
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !hasObjectClass(entry, objectClass) {
+		return false, fmt.Errorf("entry %q already exists and can't be adopted as it isn't a %s", dn, objectClass)
+	}
+
+	return true, nil
+}
+
+// adoptionWarning returns the warning reported when an existing entry is adopted
+func adoptionWarning(kind, dn string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Existing LDAP %s adopted", kind),
+		Detail:   fmt.Sprintf("LDAP %s %s already existed and has been adopted instead of being created, its attributes have been reconciled with the configuration.", kind, dn),
+	}
+}
+
+// firstAttributeValue returns the first value of the attribute or an empty string
+func firstAttributeValue(attributes map[string][]string, name string) string {
+	if val, ok := attributes[name]; ok && len(val) > 0 {
+		return val[0]
+	}
+
+	return ""
+}
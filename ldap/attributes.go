@@ -0,0 +1,351 @@
+package ldap
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// attributesResourceSchema returns the schema of the extra attributes managed by a resource
+func attributesResourceSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Extra attributes of the LDAP %s. Only the attributes listed here are managed, authoritatively: their values are replaced with the configured ones and they are removed from the %s when removed from the configuration.", kind, kind),
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description: "LDAP attribute name.",
+					Type:        schema.TypeString,
+					Required:    true,
+				},
+				"values": {
+					Description: "LDAP attribute values.",
+					Type:        schema.TypeSet,
+					Required:    true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ignoreAttributesSchema returns the schema of the attributes ignored by a resource
+func ignoreAttributesSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("LDAP attributes owned by other tools: changes on these attributes, including the ones managed by the dedicated arguments of the LDAP %s, are ignored.", kind),
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// attributesDataSourceSchema returns the schema of the extra attributes returned by a data source
+func attributesDataSourceSchema(kind, namesField string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Extra attributes of the LDAP %s listed in `%s`.", kind, namesField),
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Description: "LDAP attribute name.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"values": {
+					Description: "LDAP attribute values.",
+					Type:        schema.TypeSet,
+					Computed:    true,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+			},
+		},
+	}
+}
+
+// attributeNamesSchema returns the schema of the extra attributes names to read in a data source
+func attributeNamesSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Names of the extra LDAP attributes of the %s to return in `attributes`.", kind),
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// expandAttributes converts the attributes set to a map of attribute values by name
+func expandAttributes(v interface{}) map[string][]string {
+	attributes := map[string][]string{}
+	for _, raw := range v.(*schema.Set).List() {
+		attribute := raw.(map[string]interface{})
+
+		values := []string{}
+		for _, value := range attribute["values"].(*schema.Set).List() {
+			values = append(values, value.(string))
+		}
+
+		attributes[attribute["name"].(string)] = values
+	}
+
+	return attributes
+}
+
+// flattenAttributes converts a map of attribute values by name to the attributes set
+func flattenAttributes(attributes map[string][]string) []interface{} {
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []interface{}{}
+	for _, name := range names {
+		result = append(result, map[string]interface{}{
+			"name":   name,
+			"values": attributes[name],
+		})
+	}
+
+	return result
+}
+
+// expandStringSet converts a set of strings to a string slice
+func expandStringSet(v interface{}) []string {
+	values := []string{}
+	for _, value := range v.(*schema.Set).List() {
+		values = append(values, value.(string))
+	}
+
+	return values
+}
+
+// managedAttributeNames returns the names of the extra attributes to read:
+// the ones listed in `attribute_names` for a data source, or the ones managed
+// in `attributes` for a resource
+func managedAttributeNames(ctx context.Context, d *schema.ResourceData) []string {
+	names := []string{}
+
+	if ctx.Value(CallerTypeKey) == DatasourceCaller {
+		for _, name := range d.Get("attribute_names").(*schema.Set).List() {
+			names = append(names, name.(string))
+		}
+		return names
+	}
+
+	for name := range expandAttributes(d.Get("attributes")) {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// readAttributes reads the values of the given attributes of the entry, an
+// attribute without value is returned with an empty list
+func (c *providerClient) readAttributes(dn string, names []string) (map[string][]string, error) {
+	attributes := map[string][]string{}
+	if len(names) == 0 {
+		return attributes, nil
+	}
+
+	entry, err := c.readEntry(dn, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		values, err := decodeAttributeValues(entry, name)
+		if err != nil {
+			return nil, err
+		}
+		attributes[name] = values
+	}
+
+	return attributes, nil
+}
+
+// binaryAttributeDecoders are the decoders of the well-known binary attributes,
+// by lowercase attribute name
+var binaryAttributeDecoders = map[string]func([]byte) (string, error){
+	"objectsid":             decodeSID,
+	"sidhistory":            decodeSID,
+	"tokengroups":           decodeSID,
+	"objectguid":            decodeGUID,
+	"msexchmailboxguid":     decodeGUID,
+	"ms-ds-consistencyguid": decodeGUID,
+	"thumbnailphoto":        encodeBase64,
+	"jpegphoto":             encodeBase64,
+	"usercertificate":       encodeBase64,
+	"usersmimecertificate":  encodeBase64,
+}
+
+// decodeAttributeValues returns the values of the entry attribute as strings,
+// decoding the well-known binary attributes: SIDs and GUIDs to their string
+// form and other binary attributes to base64
+func decodeAttributeValues(entry *ldap.Entry, name string) ([]string, error) {
+	values := []string{}
+
+	decoder, ok := binaryAttributeDecoders[strings.ToLower(name)]
+	if !ok {
+		return append(values, entry.GetEqualFoldAttributeValues(name)...), nil
+	}
+
+	for _, raw := range entry.GetEqualFoldRawAttributeValues(name) {
+		value, err := decoder(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding attribute %q of %q: %w", name, entry.DN, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// encodeBase64 encodes a binary value to base64
+func encodeBase64(value []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// updateAttributes replaces the values of the attributes in new and
+// removes the attributes which are in old but not in new
+func (c *providerClient) updateAttributes(dn string, old, new map[string][]string) error {
+	req := ldap.NewModifyRequest(dn, nil)
+
+	for name, values := range new {
+		req.Replace(name, values)
+	}
+
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			req.Replace(name, []string{})
+		}
+	}
+
+	if len(req.Changes) == 0 {
+		return nil
+	}
+
+	return c.Conn.Modify(req)
+}
+
+// replaceAttribute replaces the values of a single attribute of the entry
+func (c *providerClient) replaceAttribute(dn, name string, values []string) error {
+	req := ldap.NewModifyRequest(dn, nil)
+	req.Replace(name, values)
+
+	return c.Conn.Modify(req)
+}
+
+// readExtraAttributes sets the extra attributes of the entry in `attributes`
+func readExtraAttributes(ctx context.Context, client *providerClient, dn string, d *schema.ResourceData) error {
+	attributes, err := client.readAttributes(dn, managedAttributeNames(ctx, d))
+	if err != nil {
+		return err
+	}
+
+	return d.Set("attributes", flattenAttributes(attributes))
+}
+
+// ignoredAttributeDiffSuppress returns a DiffSuppressFunc ignoring the changes
+// of an argument when its LDAP attribute is listed in `ignore_attributes`
+func ignoredAttributeDiffSuppress(ldapName string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return isIgnoredAttribute(d, ldapName)
+	}
+}
+
+// isIgnoredAttribute returns true if the attribute is listed in `ignore_attributes`
+func isIgnoredAttribute(d *schema.ResourceData, name string) bool {
+	for _, ignored := range d.Get("ignore_attributes").(*schema.Set).List() {
+		if strings.EqualFold(ignored.(string), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// customizeDiffCheckAttributes returns a CustomizeDiffFunc rejecting extra
+// attributes managed by dedicated arguments, listed in `ignore_attributes`
+// or listed twice
+func customizeDiffCheckAttributes(reserved []string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		ignored := map[string]bool{}
+		for _, name := range d.Get("ignore_attributes").(*schema.Set).List() {
+			ignored[strings.ToLower(name.(string))] = true
+		}
+
+		seen := map[string]bool{}
+		for _, raw := range d.Get("attributes").(*schema.Set).List() {
+			name := raw.(map[string]interface{})["name"].(string)
+			lowerName := strings.ToLower(name)
+
+			for _, reservedName := range reserved {
+				if strings.EqualFold(name, reservedName) {
+					return fmt.Errorf("attribute %q can't be set in attributes as it is managed by the resource", name)
+				}
+			}
+
+			if ignored[lowerName] {
+				return fmt.Errorf("attribute %q can't be both set in attributes and listed in ignore_attributes", name)
+			}
+
+			if seen[lowerName] {
+				return fmt.Errorf("attribute %q is set more than once in attributes", name)
+			}
+			seen[lowerName] = true
+		}
+
+		return nil
+	}
+}
+
+// updateStringAttributes replaces the LDAP attributes of the changed single valued
+// arguments, given as a map of argument name to LDAP attribute name. An empty value
+// removes the attribute.
+func updateStringAttributes(client *providerClient, dn string, d *schema.ResourceData, attributes map[string]string) error {
+	for argument, ldapAttribute := range attributes {
+		if !d.HasChange(argument) {
+			continue
+		}
+
+		values := []string{}
+		if value := d.Get(argument).(string); value != "" {
+			values = append(values, value)
+		}
+		if err := client.replaceAttribute(dn, ldapAttribute, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileStringAttributes replaces the LDAP attributes of the configured single
+// valued arguments whose value differs in entry, skipping the ignored attributes
+func reconcileStringAttributes(client *providerClient, entry *ldap.Entry, d *schema.ResourceData, attributes map[string]string) error {
+	for argument, ldapAttribute := range attributes {
+		value, ok := d.GetOk(argument)
+		if !ok || isIgnoredAttribute(d, ldapAttribute) || value.(string) == entry.GetAttributeValue(ldapAttribute) {
+			continue
+		}
+		if err := client.replaceAttribute(entry.DN, ldapAttribute, []string{value.(string)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
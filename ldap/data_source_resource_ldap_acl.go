@@ -0,0 +1,204 @@
+package ldap
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceLDAPACL() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_acl` is a data source for reading the owner and the DACL of an LDAP object.",
+		ReadContext: dataSourceLDAPACLRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"dn": {
+				Description: "The DN of the LDAP object.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"owner_sid": {
+				Description: "The SID of the owner of the LDAP object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"protected": {
+				Description: "Whether the DACL is protected from the inheritance of its parent ACEs.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"entries": {
+				Description: "The ACEs of the DACL, in order.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Description: "The ACE type: `allow`, `deny`, or `unsupported` for the ACEs which can't be decoded.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"trustee_sid": {
+							Description: "The SID of the trustee.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"trustee": {
+							Description: "The DN of the trustee, or its SID if it can't be resolved.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"access_mask": {
+							Description: "The access mask of the ACE.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"rights": {
+							Description: "The access rights of the ACE.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"object_type": {
+							Description: "The GUID of the object type.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"object_type_name": {
+							Description: "The name of the object type, if found in the schema or the extended rights.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"inherited_object_type": {
+							Description: "The GUID of the inherited object type.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"inherited_object_type_name": {
+							Description: "The name of the inherited object type, if found in the schema.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"inheritance": {
+							Description: "The inheritance of the ACE (`none`, `all`, `descendents`, `self_and_children` or `children`), empty if it matches none of them.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"inherited": {
+							Description: "Whether the ACE is inherited from a parent object.",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"sddl": {
+							Description: "The SDDL string of the ACE.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLDAPACLRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("dn").(string)
+
+	sd, err := client.readSecurityDescriptor(dn, sdFlagsOwner|sdFlagsDACL)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	aces := []ace{}
+	if sd.DACL != nil {
+		aces = sd.DACL.ACEs
+	}
+
+	sids := []string{}
+	guids := []string{}
+	seen := map[string]bool{}
+	for _, entry := range aces {
+		if entry.SID != "" && !seen[entry.SID] {
+			seen[entry.SID] = true
+			sids = append(sids, entry.SID)
+		}
+		for _, guid := range []string{entry.ObjectType, entry.InheritedObjectType} {
+			if guid != "" && !seen[guid] {
+				seen[guid] = true
+				guids = append(guids, guid)
+			}
+		}
+	}
+
+	// SIDs without an object in the domain, like Everyone, are kept as trustees
+	trustees, err := client.resolveSIDs(sids)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	trusteesBySID := map[string]string{}
+	for i, sid := range sids {
+		trusteesBySID[sid] = trustees[i]
+	}
+
+	names, err := client.resolveObjectTypeNames(guids)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	entries := []interface{}{}
+	for _, entry := range aces {
+		aceType := "unsupported"
+		switch {
+		case entry.Raw != nil:
+		case entry.Type == aceTypeAccessAllowed || entry.Type == aceTypeAccessAllowedObject:
+			aceType = "allow"
+		case entry.Type == aceTypeAccessDenied || entry.Type == aceTypeAccessDeniedObject:
+			aceType = "deny"
+		}
+
+		sddl := ""
+		if entry.Raw == nil {
+			sddl = entry.sddl()
+		}
+
+		entries = append(entries, map[string]interface{}{
+			"type":                       aceType,
+			"trustee_sid":                entry.SID,
+			"trustee":                    trusteesBySID[entry.SID],
+			"access_mask":                int(entry.Mask),
+			"rights":                     flattenAccessRights(entry.Mask),
+			"object_type":                entry.ObjectType,
+			"object_type_name":           names[strings.ToLower(entry.ObjectType)],
+			"inherited_object_type":      entry.InheritedObjectType,
+			"inherited_object_type_name": names[strings.ToLower(entry.InheritedObjectType)],
+			"inheritance":                flattenACEInheritance(entry.Flags),
+			"inherited":                  entry.Flags&aceFlagInherited != 0,
+			"sddl":                       sddl,
+		})
+	}
+
+	d.SetId(dn)
+
+	if err := d.Set("owner_sid", sd.Owner); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("protected", sd.Control&sdControlDACLProtected != 0); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("entries", entries)
+
+	return diag.FromErr(err)
+}
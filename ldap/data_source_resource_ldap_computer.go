@@ -0,0 +1,116 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLDAPComputer() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_computer` is a data source for reading an LDAP computer account.",
+		ReadContext: dataSourceLDAPComputerRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP computer.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where LDAP computer will be search. Defaults to the domain naming context. Set to the OU of the computer once read.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"scope": {
+				Description:  "LDAP search scope (1: SingleLevel, 2: WholeSubtree). Default is `2`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(1, 2),
+			},
+			"name": {
+				Description: "LDAP computer name or sAMAccountName, with or without the trailing `$`.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"sam_account_name": {
+				Description: "The sAMAccountName (pre-Windows 2000 name) of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"dns_host_name": {
+				Description: "The dNSHostName of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"description": {
+				Description: "Description attribute for the LDAP computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"operating_system": {
+				Description: "The operatingSystem of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"operating_system_version": {
+				Description: "The operatingSystemVersion of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"operating_system_service_pack": {
+				Description: "The operatingSystemServicePack of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"managed_by": {
+				Description: "ManagedBy attribute",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"enabled": {
+				Description: "Whether the computer account is enabled",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"service_principal_names": {
+				Description: "Service principal names of the computer",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"object_sid": {
+				Description: "The SID of the computer",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"attribute_names": attributeNamesSchema("computer"),
+			"attributes":      attributesDataSourceSchema("computer", "attribute_names"),
+		},
+	}
+}
+
+func dataSourceLDAPComputerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	baseDN, err := client.baseDNOrDefault(d.Get("ou").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dn, err := client.searchComputerByName(d.Get("name").(string), baseDN, d.Get("scope").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	// Add context key to signal the Read is called from a datasource
+	return resourceLDAPComputerRead(context.WithValue(ctx, CallerTypeKey, DatasourceCaller), d, m)
+}
@@ -0,0 +1,241 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// pwdProperties flags of the domain object
+const (
+	pwdPropertiesComplex        = 0x1
+	pwdPropertiesStoreCleartext = 0x10
+)
+
+// domainIntAttributes maps the ldap_domain integer attributes to their domain object attribute
+var domainIntAttributes = map[string]string{
+	"functional_level":        "msDS-Behavior-Version",
+	"min_password_length":     "minPwdLength",
+	"password_history_length": "pwdHistoryLength",
+	"lockout_threshold":       "lockoutThreshold",
+}
+
+// domainDurationAttributes maps the ldap_domain duration attributes to their domain object attribute
+var domainDurationAttributes = map[string]string{
+	"max_password_age":           "maxPwdAge",
+	"min_password_age":           "minPwdAge",
+	"lockout_duration":           "lockoutDuration",
+	"lockout_observation_window": "lockOutObservationWindow",
+}
+
+func dataSourceLDAPDomain() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_domain` is a data source for reading the Active Directory domain of the LDAP server and its default password and lockout policy.",
+		ReadContext: dataSourceLDAPDomainRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the domain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"dn": {
+				Description: "The DN of the domain (the default naming context).",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"dns_name": {
+				Description: "The DNS name of the domain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"netbios_name": {
+				Description: "The NetBIOS name of the domain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"sid": {
+				Description: "The SID of the domain.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"functional_level": {
+				Description: "The functional level of the domain (msDS-Behavior-Version, e.g. `7` for Windows Server 2016).",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"min_password_length": {
+				Description: "The minimum password length of the default password policy.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"password_history_length": {
+				Description: "The number of remembered passwords of the default password policy.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"max_password_age": {
+				Description: "The maximum password age of the default password policy as a duration (e.g. `1008h0m0s`), `0s` if passwords never expire.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"min_password_age": {
+				Description: "The minimum password age of the default password policy as a duration.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"password_complexity": {
+				Description: "Whether passwords must meet complexity requirements.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"reversible_encryption": {
+				Description: "Whether passwords are stored using reversible encryption.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"lockout_threshold": {
+				Description: "The number of failed logons before an account is locked out, `0` if accounts are never locked out.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"lockout_duration": {
+				Description: "The lockout duration as a duration, `0s` if locked out accounts must be unlocked by an administrator.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"lockout_observation_window": {
+				Description: "The duration after which the failed logons counter is reset.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceLDAPDomainRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	rootDSE, err := client.readRootDSE([]string{"defaultNamingContext", "configurationNamingContext"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	domainDN := rootDSE.GetAttributeValue("defaultNamingContext")
+	if domainDN == "" {
+		return diag.Errorf("the LDAP server root DSE has no defaultNamingContext")
+	}
+
+	attributes := []string{"objectSid", "pwdProperties"}
+	for _, domainAttribute := range domainIntAttributes {
+		attributes = append(attributes, domainAttribute)
+	}
+	for _, domainAttribute := range domainDurationAttributes {
+		attributes = append(attributes, domainAttribute)
+	}
+
+	domain, err := client.readEntry(domainDN, attributes)
+	if err != nil {
+		return diag.Errorf("failed reading domain %s: %s", domainDN, err)
+	}
+
+	sid, err := decodeSID(domain.GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return diag.Errorf("failed decoding objectSid of %s: %s", domainDN, err)
+	}
+
+	// The DNS and NetBIOS names are stored on the domain crossRef object in the configuration partition
+	filter := fmt.Sprintf("(&(objectClass=crossRef)(nCName=%s))", ldap.EscapeFilter(domainDN))
+	crossRefs, err := client.search("CN=Partitions,"+rootDSE.GetAttributeValue("configurationNamingContext"), ldap.ScopeSingleLevel, filter, []string{"dnsRoot", "nETBIOSName"})
+	if err != nil {
+		return diag.Errorf("failed searching crossRef of domain %s: %s", domainDN, err)
+	}
+
+	dnsName := dnsNameFromDN(domainDN)
+	netbiosName := ""
+	if len(crossRefs) == 1 {
+		if dnsRoot := crossRefs[0].GetAttributeValue("dnsRoot"); dnsRoot != "" {
+			dnsName = dnsRoot
+		}
+		netbiosName = crossRefs[0].GetAttributeValue("nETBIOSName")
+	}
+
+	pwdProperties, err := parseIntAttribute(domain, "pwdProperties")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(domainDN)
+
+	values := map[string]interface{}{
+		"dn":                    domainDN,
+		"dns_name":              dnsName,
+		"netbios_name":          netbiosName,
+		"sid":                   sid,
+		"password_complexity":   pwdProperties&pwdPropertiesComplex != 0,
+		"reversible_encryption": pwdProperties&pwdPropertiesStoreCleartext != 0,
+	}
+
+	for attribute, domainAttribute := range domainIntAttributes {
+		value, err := parseIntAttribute(domain, domainAttribute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		values[attribute] = value
+	}
+
+	for attribute, domainAttribute := range domainDurationAttributes {
+		value, err := flattenPasswordPolicyDuration(attribute, domain.GetAttributeValue(domainAttribute))
+		if err != nil {
+			return diag.Errorf("failed converting %s of %s: %s", domainAttribute, domainDN, err)
+		}
+		values[attribute] = value
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// parseIntAttribute returns the integer value of an entry attribute, 0 if it has no value
+func parseIntAttribute(entry *ldap.Entry, name string) (int, error) {
+	value := entry.GetAttributeValue(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q of %s: %w", name, value, entry.DN, err)
+	}
+
+	return i, nil
+}
+
+// dnsNameFromDN returns the DNS name made of the DC components of a DN
+func dnsNameFromDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return ""
+	}
+
+	labels := []string{}
+	for _, rdn := range parsed.RDNs {
+		for _, attribute := range rdn.Attributes {
+			if strings.EqualFold(attribute.Type, "DC") {
+				labels = append(labels, attribute.Value)
+			}
+		}
+	}
+
+	return strings.Join(labels, ".")
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Ouest-France/goldap"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -72,6 +71,13 @@ func dataSourceLDAPGroup() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"sam_account_name": {
+				Description: "The sAMAccountName (pre-Windows 2000 name) of the group",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"attribute_names": attributeNamesSchema("group"),
+			"attributes":      attributesDataSourceSchema("group", "attribute_names"),
 		},
 	}
 }
@@ -91,7 +97,7 @@ func dataSourceLDAPGroupRead(ctx context.Context, d *schema.ResourceData, m inte
 		d.SetId(dn)
 	} else {
 		// If scope is 1 or 2, we search the group DN given the group name and the OU
-		client := m.(*goldap.Client)
+		client := m.(*providerClient)
 
 		// Search group
 		dn, err := client.SearchGroupByName(d.Get("name").(string), d.Get("ou").(string), scope)
@@ -0,0 +1,184 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// groupMemberAttributes are the attributes read for each group member
+var groupMemberAttributes = []string{"distinguishedName", "name", "objectClass", "sAMAccountName"}
+
+func dataSourceLDAPGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_group_members` is a data source for retrieving the direct or transitive members of an LDAP group.",
+		ReadContext: dataSourceLDAPGroupMembersRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP group.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"group_dn": {
+				Description: "The DN of the LDAP group.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"recursive": {
+				Description: "Return the members of nested groups too. LDAP_MATCHING_RULE_IN_CHAIN is used on Active Directory, groups are expanded by the provider otherwise. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"members": {
+				Description: "LDAP group members, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Description: "The DN of the member.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the member.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"object_class": {
+							Description: "The most specific objectClass of the member (e.g. `user`, `group`, `computer`).",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"sam_account_name": {
+							Description: "The sAMAccountName of the member, empty if it has none.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLDAPGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	groupDN := d.Get("group_dn").(string)
+
+	useInChain := false
+	if d.Get("recursive").(bool) {
+		activeDirectory, err := client.isActiveDirectory()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		useInChain = activeDirectory
+	}
+
+	var entries []*ldap.Entry
+	var err error
+	if useInChain {
+		entries, err = client.searchGroupMembersInChain(groupDN)
+	} else {
+		entries, err = client.readGroupMembers(groupDN, d.Get("recursive").(bool))
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(groupDN)
+
+	err = d.Set("members", flattenGroupMembers(entries))
+
+	return diag.FromErr(err)
+}
+
+// searchGroupMembersInChain searches the transitive members of a group using LDAP_MATCHING_RULE_IN_CHAIN
+func (c *providerClient) searchGroupMembersInChain(groupDN string) ([]*ldap.Entry, error) {
+	rootDSE, err := c.readRootDSE([]string{"defaultNamingContext"})
+	if err != nil {
+		return nil, err
+	}
+
+	filter := fmt.Sprintf("(memberOf:%s:=%s)", matchingRuleInChain, ldap.EscapeFilter(groupDN))
+
+	return c.search(rootDSE.GetAttributeValue("defaultNamingContext"), ldap.ScopeWholeSubtree, filter, groupMemberAttributes)
+}
+
+// readGroupMembers reads the members of a group, expanding the nested groups
+// if recursive is set. Groups already expanded are skipped to handle cycles.
+func (c *providerClient) readGroupMembers(groupDN string, recursive bool) ([]*ldap.Entry, error) {
+	members := map[string]*ldap.Entry{}
+	expanded := map[string]bool{strings.ToLower(groupDN): true}
+	queue := []string{groupDN}
+
+	for len(queue) > 0 {
+		dn := queue[0]
+		queue = queue[1:]
+
+		attributes, err := c.ReadGroup(dn, 1500)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading group %q: %w", dn, err)
+		}
+
+		for _, memberDN := range attributes["member"] {
+			key := strings.ToLower(memberDN)
+
+			entry, ok := members[key]
+			if !ok {
+				entry, err = c.readEntry(memberDN, groupMemberAttributes)
+				if err != nil {
+					return nil, fmt.Errorf("failed reading member %q of group %q: %w", memberDN, dn, err)
+				}
+				members[key] = entry
+			}
+
+			if recursive && hasObjectClass(entry, "group") && !expanded[key] {
+				expanded[key] = true
+				queue = append(queue, memberDN)
+			}
+		}
+	}
+
+	entries := []*ldap.Entry{}
+	for _, entry := range members {
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// flattenGroupMembers converts member entries to the members list, sorted by DN
+func flattenGroupMembers(entries []*ldap.Entry) []interface{} {
+	sortEntriesByDN(entries)
+
+	members := []interface{}{}
+	for _, entry := range entries {
+		members = append(members, map[string]interface{}{
+			"dn":               entry.DN,
+			"name":             entry.GetAttributeValue("name"),
+			"object_class":     mostSpecificObjectClass(entry),
+			"sam_account_name": entry.GetAttributeValue("sAMAccountName"),
+		})
+	}
+
+	return members
+}
+
+// mostSpecificObjectClass returns the last objectClass of the entry,
+// which is the most specific one on Active Directory
+func mostSpecificObjectClass(entry *ldap.Entry) string {
+	objectClasses := entry.GetAttributeValues("objectClass")
+	if len(objectClasses) == 0 {
+		return ""
+	}
+
+	return objectClasses[len(objectClasses)-1]
+}
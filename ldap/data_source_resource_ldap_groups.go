@@ -0,0 +1,140 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLDAPGroups() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_groups` is a data source for listing LDAP groups.",
+		ReadContext: dataSourceLDAPGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The search base DN and filter.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where LDAP groups will be search. Defaults to the domain naming context.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"scope": {
+				Description:  "LDAP search scope (1: SingleLevel, 2: WholeSubtree). Default is `2`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(1, 2),
+			},
+			"name_prefix": {
+				Description: "Only return the LDAP groups whose name starts with this prefix.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"filter": {
+				Description: "Raw LDAP filter the LDAP groups must match.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"include_member_count": {
+				Description: "Count the direct members of each group in `member_count`, which requires reading all their members. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"groups": {
+				Description: "LDAP groups, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Description: "The DN of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"sam_account_name": {
+							Description: "The sAMAccountName of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"mail": {
+							Description: "The mail of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"description": {
+							Description: "The description of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"group_type": {
+							Description: "The type of the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"member_count": {
+							Description: "The number of direct members of the group, `0` unless `include_member_count` is set.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLDAPGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	baseDN, err := client.baseDNOrDefault(d.Get("ou").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := buildListFilter("(objectClass=group)", d.Get("name_prefix").(string), d.Get("filter").(string))
+
+	entries, err := client.search(baseDN, d.Get("scope").(int), filter, []string{"name", "sAMAccountName", "mail", "description", "groupType"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sortEntriesByDN(entries)
+
+	groups := []interface{}{}
+	for _, entry := range entries {
+		memberCount := 0
+		if d.Get("include_member_count").(bool) {
+			memberCount, err = client.countAttributeValues(entry.DN, "member")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		groups = append(groups, map[string]interface{}{
+			"dn":               entry.DN,
+			"name":             entry.GetAttributeValue("name"),
+			"sam_account_name": entry.GetAttributeValue("sAMAccountName"),
+			"mail":             entry.GetAttributeValue("mail"),
+			"description":      entry.GetAttributeValue("description"),
+			"group_type":       entry.GetAttributeValue("groupType"),
+			"member_count":     memberCount,
+		})
+	}
+
+	d.SetId(baseDN + filter)
+
+	err = d.Set("groups", groups)
+
+	return diag.FromErr(err)
+}
@@ -3,12 +3,37 @@ package ldap
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/Ouest-France/goldap"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// ouChildSchema is the schema of an object listed in the ldap_ou data source
+var ouChildSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"dn": {
+			Description: "The DN of the object.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"name": {
+			Description: "The name of the object.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
+// ouChildrenAttributes maps the ldap_ou data source attributes listing children to their objectClasses
+var ouChildrenAttributes = map[string][]string{
+	"child_ous": {"organizationalUnit"},
+	"groups":    {"group"},
+	"users":     {"user", "inetOrgPerson"},
+	"computers": {"computer"},
+}
+
 func dataSourceLDAPOU() *schema.Resource {
 	return &schema.Resource{
 		Description: "`ldap_ou` is a data source for getting an LDAP OU.",
@@ -46,6 +71,52 @@ func dataSourceLDAPOU() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"gpo_links": gpoLinksDataSourceSchema("OU"),
+			"block_inheritance": {
+				Description: "Whether the inheritance of the Group Policy links of the parent OUs is blocked.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"recursive": {
+				Description: "List the whole OU subtree in the children attributes instead of the direct children only. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"child_ous": {
+				Description: "Child OUs of the LDAP OU, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        ouChildSchema,
+			},
+			"groups": {
+				Description: "Groups in the LDAP OU, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        ouChildSchema,
+			},
+			"users": {
+				Description: "Users in the LDAP OU, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        ouChildSchema,
+			},
+			"computers": {
+				Description: "Computers in the LDAP OU, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        ouChildSchema,
+			},
+			"object_counts": {
+				Description: "Number of objects in the LDAP OU by objectClass.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"attribute_names": attributeNamesSchema("OU"),
+			"attributes":      attributesDataSourceSchema("OU", "attribute_names"),
 		},
 	}
 }
@@ -59,7 +130,7 @@ func dataSourceLDAPOURead(ctx context.Context, d *schema.ResourceData, m interfa
 	}
 
 	// If scope is 1 or 2, we search the OU DN given the OU name and the OU
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	// Search OU
 	dn, err := client.SearchOUByName(d.Get("name").(string), d.Get("ou").(string), scope)
@@ -70,5 +141,62 @@ func dataSourceLDAPOURead(ctx context.Context, d *schema.ResourceData, m interfa
 	d.SetId(dn)
 
 	// Add context key to signal the Read is called from a datasource
-	return resourceLDAPOURead(context.WithValue(ctx, CallerTypeKey, DatasourceCaller), d, m)
+	if diags := resourceLDAPOURead(context.WithValue(ctx, CallerTypeKey, DatasourceCaller), d, m); diags.HasError() {
+		return diags
+	}
+
+	err = readOUChildren(client, d)
+
+	return diag.FromErr(err)
+}
+
+// readOUChildren sets the children of the OU by objectClass and their count
+func readOUChildren(client *providerClient, d *schema.ResourceData) error {
+	scope := ldap.ScopeSingleLevel
+	if d.Get("recursive").(bool) {
+		scope = ldap.ScopeWholeSubtree
+	}
+
+	entries, err := client.search(d.Id(), scope, "(objectClass=*)", []string{"name", "objectClass"})
+	if err != nil {
+		return err
+	}
+	sortEntriesByDN(entries)
+
+	children := map[string][]interface{}{}
+	for attribute := range ouChildrenAttributes {
+		children[attribute] = []interface{}{}
+	}
+	counts := map[string]interface{}{}
+
+	for _, entry := range entries {
+		// A subtree search returns the OU itself
+		if strings.EqualFold(entry.DN, d.Id()) {
+			continue
+		}
+
+		objectClass := mostSpecificObjectClass(entry)
+
+		count, _ := counts[objectClass].(int)
+		counts[objectClass] = count + 1
+
+		for attribute, childObjectClasses := range ouChildrenAttributes {
+			for _, childObjectClass := range childObjectClasses {
+				if strings.EqualFold(objectClass, childObjectClass) {
+					children[attribute] = append(children[attribute], map[string]interface{}{
+						"dn":   entry.DN,
+						"name": entry.GetAttributeValue("name"),
+					})
+				}
+			}
+		}
+	}
+
+	for attribute, values := range children {
+		if err := d.Set(attribute, values); err != nil {
+			return err
+		}
+	}
+
+	return d.Set("object_counts", counts)
 }
@@ -0,0 +1,105 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// rootDSEStringAttributes maps the ldap_root_dse single valued attributes to their root DSE attribute
+var rootDSEStringAttributes = map[string]string{
+	"default_naming_context":          "defaultNamingContext",
+	"configuration_naming_context":    "configurationNamingContext",
+	"schema_naming_context":           "schemaNamingContext",
+	"root_domain_naming_context":      "rootDomainNamingContext",
+	"dns_host_name":                   "dnsHostName",
+	"server_name":                     "serverName",
+	"ds_service_name":                 "dsServiceName",
+	"vendor_name":                     "vendorName",
+	"vendor_version":                  "vendorVersion",
+	"domain_functionality":            "domainFunctionality",
+	"forest_functionality":            "forestFunctionality",
+	"domain_controller_functionality": "domainControllerFunctionality",
+}
+
+// rootDSEListAttributes maps the ldap_root_dse multi valued attributes to their root DSE attribute
+var rootDSEListAttributes = map[string]string{
+	"naming_contexts":           "namingContexts",
+	"supported_controls":        "supportedControl",
+	"supported_extensions":      "supportedExtension",
+	"supported_capabilities":    "supportedCapabilities",
+	"supported_sasl_mechanisms": "supportedSASLMechanisms",
+	"supported_ldap_versions":   "supportedLDAPVersion",
+}
+
+func dataSourceLDAPRootDSE() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"id": {
+			Description: "The DNS host name of the server.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+
+	for attribute, rootDSEAttribute := range rootDSEStringAttributes {
+		s[attribute] = &schema.Schema{
+			Description: "The " + rootDSEAttribute + " attribute of the root DSE.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		}
+	}
+
+	for attribute, rootDSEAttribute := range rootDSEListAttributes {
+		s[attribute] = &schema.Schema{
+			Description: "The " + rootDSEAttribute + " attribute values of the root DSE.",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+
+	return &schema.Resource{
+		Description: "`ldap_root_dse` is a data source for reading the LDAP server root DSE.",
+		ReadContext: dataSourceLDAPRootDSERead,
+		Schema:      s,
+	}
+}
+
+func dataSourceLDAPRootDSERead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	attributes := []string{}
+	for _, rootDSEAttribute := range rootDSEStringAttributes {
+		attributes = append(attributes, rootDSEAttribute)
+	}
+	for _, rootDSEAttribute := range rootDSEListAttributes {
+		attributes = append(attributes, rootDSEAttribute)
+	}
+
+	rootDSE, err := client.readRootDSE(attributes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for attribute, rootDSEAttribute := range rootDSEStringAttributes {
+		if err := d.Set(attribute, rootDSE.GetAttributeValue(rootDSEAttribute)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	for attribute, rootDSEAttribute := range rootDSEListAttributes {
+		if err := d.Set(attribute, rootDSE.GetAttributeValues(rootDSEAttribute)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(rootDSE.GetAttributeValue("dnsHostName"))
+	if d.Id() == "" {
+		d.SetId(client.Host)
+	}
+
+	return nil
+}
@@ -2,13 +2,23 @@ package ldap
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
-	"github.com/Ouest-France/goldap"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// userLookupArguments are the arguments which can be used to find the LDAP user,
+// at least one of them must be set
+var userLookupArguments = []string{"name", "sam_account_name", "user_principal_name", "mail", "object_sid", "object_guid", "employee_id", "filter"}
+
+// userLookupAttributes are the LDAP attributes read for the LDAP user
+var userLookupAttributes = []string{"distinguishedName", "name", "sAMAccountName", "userPrincipalName", "description", "mail", "objectSid", "objectGUID", "employeeID"}
+
 func dataSourceLDAPUser() *schema.Resource {
 	return &schema.Resource{
 		Description: "`ldap_user` is a data source for retrieving an LDAP user.",
@@ -20,27 +30,34 @@ func dataSourceLDAPUser() *schema.Resource {
 				Computed:    true,
 			},
 			"ou": {
-				Description: "OU where LDAP user will be search.",
+				Description: "OU where LDAP user will be search. Defaults to the domain naming context.",
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+			},
+			"scope": {
+				Description:  "LDAP search scope (0: BaseObject, 1: SingleLevel, 2: WholeSubtree). Default is `2`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(0, 2),
 			},
 			"name": {
 				Description:  "The name of the LDAP user.",
 				Type:         schema.TypeString,
 				Optional:     true,
-				AtLeastOneOf: []string{"name", "sam_account_name", "user_principal_name"},
+				AtLeastOneOf: userLookupArguments,
 			},
 			"sam_account_name": {
 				Description:  "The sAMAccountName of the LDAP user.",
 				Type:         schema.TypeString,
 				Optional:     true,
-				AtLeastOneOf: []string{"name", "sam_account_name", "user_principal_name"},
+				AtLeastOneOf: userLookupArguments,
 			},
 			"user_principal_name": {
 				Description:  "The userPrincipalName of the LDAP user",
 				Type:         schema.TypeString,
 				Optional:     true,
-				AtLeastOneOf: []string{"name", "sam_account_name", "user_principal_name"},
+				AtLeastOneOf: userLookupArguments,
 			},
 			"description": {
 				Description: "Description attribute for the LDAP user.",
@@ -48,7 +65,125 @@ func dataSourceLDAPUser() *schema.Resource {
 				Computed:    true,
 			},
 			"mail": {
-				Description: "Mail attribute for the LDAP user.",
+				Description:  "Mail attribute for the LDAP user.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: userLookupArguments,
+			},
+			"object_sid": {
+				Description:  "The objectSid of the LDAP user, as a SID string (S-1-5-21-...).",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: userLookupArguments,
+			},
+			"object_guid": {
+				Description:  "The objectGUID of the LDAP user, as a GUID string.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: userLookupArguments,
+			},
+			"employee_id": {
+				Description:  "The employeeID of the LDAP user.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: userLookupArguments,
+			},
+			"filter": {
+				Description:  "Raw LDAP filter the LDAP user must match, combined with the other lookup arguments.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: userLookupArguments,
+			},
+			"attributes": {
+				Description: "Names of extra LDAP attributes of the user to return in `attribute_values`.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attribute_values": attributesDataSourceSchema("user", "attributes"),
+			"user_account_control": {
+				Description: "The raw userAccountControl attribute of the LDAP user.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"enabled": {
+				Description: "Whether the LDAP user account is enabled.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"locked_out": {
+				Description: "Whether the LDAP user account is locked out.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"password_expired": {
+				Description: "Whether the LDAP user password is expired.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"password_never_expires": {
+				Description: "Whether the LDAP user password never expires.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"password_not_required": {
+				Description: "Whether the LDAP user account doesn't require a password.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"smartcard_required": {
+				Description: "Whether a smart card is required to log on with the LDAP user.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"trusted_for_delegation": {
+				Description: "Whether the LDAP user is trusted for Kerberos unconstrained delegation.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"trusted_to_auth_for_delegation": {
+				Description: "Whether the LDAP user is trusted to authenticate for delegation (protocol transition).",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"not_delegated": {
+				Description: "Whether the LDAP user account is sensitive and cannot be delegated.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"use_des_key_only": {
+				Description: "Whether the LDAP user is restricted to DES encryption types for Kerberos.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"dont_require_preauth": {
+				Description: "Whether Kerberos pre-authentication is not required for the LDAP user.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"pwd_last_set": {
+				Description: "When the LDAP user password was last set, in RFC 3339 format. Empty if the password must be changed at next logon.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_logon": {
+				Description: "When the LDAP user last logged on, in RFC 3339 format, from the replicated lastLogonTimestamp attribute (up to 14 days behind). Empty if the user never logged on.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"account_expires": {
+				Description: "When the LDAP user account expires, in RFC 3339 format. Empty if the account never expires.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"when_created": {
+				Description: "When the LDAP user was created, in RFC 3339 format.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
@@ -61,12 +196,12 @@ func dataSourceLDAPUserRead(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceLDAPUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
-	user, err := client.ReadUser(d.Get("ou").(string), ldap.EscapeFilter(d.Get("name").(string)), d.Get("sam_account_name").(string), d.Get("user_principal_name").(string))
+	user, err := searchUser(client, d)
 
 	if err != nil {
-		if err.(*ldap.Error).ResultCode == ldap.LDAPResultNoSuchObject {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
 			// Object doesn't exist
 
 			// If Read is called from a datasource, return an error
@@ -114,5 +249,192 @@ func resourceLDAPUserRead(ctx context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
+	if val, ok := user["objectSid"]; ok {
+		if err := d.Set("object_sid", val[0]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if val, ok := user["objectGUID"]; ok {
+		if err := d.Set("object_guid", val[0]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if val, ok := user["employeeID"]; ok {
+		if err := d.Set("employee_id", val[0]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := readUserAccountStatus(client, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	names := []string{}
+	for _, name := range d.Get("attributes").(*schema.Set).List() {
+		names = append(names, name.(string))
+	}
+
+	attributes, err := client.readAttributes(d.Id(), names)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("attribute_values", flattenAttributes(attributes)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diag.FromErr(err)
 }
+
+// searchUser searches the LDAP user matching all the lookup arguments and returns
+// its attributes. A NoSuchObject LDAP error is returned when no user matches and
+// an error listing the matching DNs is returned when more than one user matches.
+func searchUser(client *providerClient, d *schema.ResourceData) (map[string][]string, error) {
+	filter := "(&(objectCategory=person)(objectClass=user)"
+
+	for argument, attribute := range map[string]string{
+		"name":                "name",
+		"sam_account_name":    "sAMAccountName",
+		"user_principal_name": "userPrincipalName",
+		"mail":                "mail",
+		"employee_id":         "employeeID",
+	} {
+		if value := d.Get(argument).(string); value != "" {
+			filter += fmt.Sprintf("(%s=%s)", attribute, ldap.EscapeFilter(value))
+		}
+	}
+
+	if sid := d.Get("object_sid").(string); sid != "" {
+		binarySID, err := encodeSID(sid)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf("(objectSid=%s)", escapeBinaryFilter(binarySID))
+	}
+
+	if guid := d.Get("object_guid").(string); guid != "" {
+		binaryGUID, err := encodeGUID(guid)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf("(objectGUID=%s)", escapeBinaryFilter(binaryGUID))
+	}
+
+	if rawFilter := d.Get("filter").(string); rawFilter != "" {
+		if !strings.HasPrefix(rawFilter, "(") {
+			rawFilter = fmt.Sprintf("(%s)", rawFilter)
+		}
+		filter += rawFilter
+	}
+
+	filter += ")"
+
+	baseDN, err := client.baseDNOrDefault(d.Get("ou").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := client.search(baseDN, d.Get("scope").(int), filter, userLookupAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entries) {
+	case 0:
+		return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, fmt.Errorf("no LDAP user matching %s found in %s", filter, baseDN))
+	case 1:
+	default:
+		dns := []string{}
+		for _, entry := range entries {
+			dns = append(dns, entry.DN)
+		}
+		return nil, fmt.Errorf("%d LDAP users matching %s found in %s, the lookup must match a single user: %s", len(entries), filter, baseDN, strings.Join(dns, "; "))
+	}
+
+	user := map[string][]string{}
+	for _, attribute := range userLookupAttributes {
+		values, err := decodeAttributeValues(entries[0], attribute)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			user[attribute] = values
+		}
+	}
+	user["distinguishedName"] = []string{entries[0].DN}
+
+	return user, nil
+}
+
+// readUserAccountStatus sets the decoded userAccountControl flags and the account dates of the LDAP user
+func readUserAccountStatus(client *providerClient, d *schema.ResourceData) error {
+	entry, err := client.readEntry(d.Id(), []string{
+		"userAccountControl",
+		"msDS-User-Account-Control-Computed",
+		"pwdLastSet",
+		"lastLogonTimestamp",
+		"accountExpires",
+		"whenCreated",
+	})
+	if err != nil {
+		return err
+	}
+
+	uac, err := strconv.Atoi(entry.GetAttributeValue("userAccountControl"))
+	if err != nil {
+		return fmt.Errorf("invalid userAccountControl for %s: %w", d.Id(), err)
+	}
+
+	// msDS-User-Account-Control-Computed is a constructed attribute, missing on old servers
+	computedUAC := 0
+	if val := entry.GetAttributeValue("msDS-User-Account-Control-Computed"); val != "" {
+		if computedUAC, err = strconv.Atoi(val); err != nil {
+			return fmt.Errorf("invalid msDS-User-Account-Control-Computed for %s: %w", d.Id(), err)
+		}
+	}
+
+	if err := d.Set("user_account_control", uac); err != nil {
+		return err
+	}
+
+	if err := d.Set("enabled", uac&uacAccountDisable == 0); err != nil {
+		return err
+	}
+
+	if err := d.Set("locked_out", (uac|computedUAC)&uacLockout != 0); err != nil {
+		return err
+	}
+
+	if err := d.Set("password_expired", (uac|computedUAC)&uacPasswordExpired != 0); err != nil {
+		return err
+	}
+
+	for attribute, flag := range userAccountControlFlags {
+		if err := d.Set(attribute, uac&flag != 0); err != nil {
+			return err
+		}
+	}
+
+	for attribute, ldapAttribute := range map[string]string{
+		"pwd_last_set":    "pwdLastSet",
+		"last_logon":      "lastLogonTimestamp",
+		"account_expires": "accountExpires",
+	} {
+		value, err := fileTimeToRFC3339(entry.GetAttributeValue(ldapAttribute))
+		if err != nil {
+			return fmt.Errorf("invalid %s for %s: %w", ldapAttribute, d.Id(), err)
+		}
+		if err := d.Set(attribute, value); err != nil {
+			return err
+		}
+	}
+
+	whenCreated, err := generalizedTimeToRFC3339(entry.GetAttributeValue("whenCreated"))
+	if err != nil {
+		return fmt.Errorf("invalid whenCreated for %s: %w", d.Id(), err)
+	}
+
+	return d.Set("when_created", whenCreated)
+}
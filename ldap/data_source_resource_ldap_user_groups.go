@@ -0,0 +1,170 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// userGroupSchema is the schema of a group returned by ldap_user_groups
+var userGroupSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"dn": {
+			Description: "The DN of the group.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"name": {
+			Description: "The name of the group.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	},
+}
+
+func dataSourceLDAPUserGroups() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_user_groups` is a data source for retrieving the direct and transitive groups of an LDAP user.",
+		ReadContext: dataSourceLDAPUserGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP user.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_dn": {
+				Description: "The DN of the LDAP user.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"primary_group_dn": {
+				Description: "The DN of the primary group of the user.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"direct_groups": {
+				Description: "Groups the user is a direct member of (memberOf and the primary group), sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        userGroupSchema,
+			},
+			"groups": {
+				Description: "Security groups the user is a direct or transitive member of (tokenGroups, including the primary group), sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        userGroupSchema,
+			},
+		},
+	}
+}
+
+func dataSourceLDAPUserGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	userDN := d.Get("user_dn").(string)
+
+	// tokenGroups is a constructed attribute only returned by a base search
+	user, err := client.readEntry(userDN, []string{"memberOf", "primaryGroupID", "objectSid", "tokenGroups"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return diag.Errorf("LDAP user not found: %s", userDN)
+		}
+		return diag.FromErr(err)
+	}
+
+	userSID, err := decodeSID(user.GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return diag.Errorf("failed decoding objectSid of %s: %s", userDN, err)
+	}
+
+	// The primary group SID is the user domain SID followed by the primaryGroupID RID.
+	// Objects without primaryGroupID, like contacts, have no primary group.
+	primaryGroupSID := ""
+	sids := []string{}
+	if primaryGroupID := user.GetAttributeValue("primaryGroupID"); primaryGroupID != "" {
+		primaryGroupSID = fmt.Sprintf("%s-%s", userSID[:strings.LastIndex(userSID, "-")], primaryGroupID)
+		sids = append(sids, primaryGroupSID)
+	}
+
+	for _, raw := range user.GetRawAttributeValues("tokenGroups") {
+		sid, err := decodeSID(raw)
+		if err != nil {
+			return diag.Errorf("failed decoding tokenGroups of %s: %s", userDN, err)
+		}
+		if sid != primaryGroupSID {
+			sids = append(sids, sid)
+		}
+	}
+
+	baseDN, err := client.baseDNOrDefault("")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// SIDs of groups outside of the domain can't be resolved and are skipped
+	entries, err := client.searchBySIDs(baseDN, sids, []string{"name", "objectSid"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	groupsByDN := map[string]*ldap.Entry{}
+	primaryGroupDN := ""
+	for _, entry := range entries {
+		groupsByDN[strings.ToLower(entry.DN)] = entry
+
+		if sid, err := decodeSID(entry.GetRawAttributeValue("objectSid")); err == nil && sid == primaryGroupSID {
+			primaryGroupDN = entry.DN
+		}
+	}
+
+	// Distribution groups aren't part of tokenGroups and must be read
+	directGroups := []*ldap.Entry{}
+	for _, dn := range user.GetAttributeValues("memberOf") {
+		entry, ok := groupsByDN[strings.ToLower(dn)]
+		if !ok {
+			entry, err = client.readEntry(dn, []string{"name"})
+			if err != nil {
+				return diag.Errorf("failed reading group %s: %s", dn, err)
+			}
+		}
+		directGroups = append(directGroups, entry)
+	}
+	if primaryGroup, ok := groupsByDN[strings.ToLower(primaryGroupDN)]; ok {
+		directGroups = append(directGroups, primaryGroup)
+	}
+
+	d.SetId(userDN)
+
+	if err := d.Set("primary_group_dn", primaryGroupDN); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("direct_groups", flattenUserGroups(directGroups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("groups", flattenUserGroups(entries))
+
+	return diag.FromErr(err)
+}
+
+// flattenUserGroups converts group entries to a list of groups, sorted by DN
+func flattenUserGroups(entries []*ldap.Entry) []interface{} {
+	sorted := append([]*ldap.Entry{}, entries...)
+	sortEntriesByDN(sorted)
+
+	groups := []interface{}{}
+	for _, entry := range sorted {
+		groups = append(groups, map[string]interface{}{
+			"dn":   entry.DN,
+			"name": entry.GetAttributeValue("name"),
+		})
+	}
+
+	return groups
+}
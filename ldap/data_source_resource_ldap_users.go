@@ -0,0 +1,120 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceLDAPUsers() *schema.Resource {
+	return &schema.Resource{
+		Description: "`ldap_users` is a data source for listing LDAP users.",
+		ReadContext: dataSourceLDAPUsersRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The search base DN and filter.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where LDAP users will be search. Defaults to the domain naming context.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"scope": {
+				Description:  "LDAP search scope (1: SingleLevel, 2: WholeSubtree). Default is `2`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(1, 2),
+			},
+			"name_prefix": {
+				Description: "Only return the LDAP users whose name starts with this prefix.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"filter": {
+				Description: "Raw LDAP filter the LDAP users must match.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"users": {
+				Description: "LDAP users, sorted by DN.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"dn": {
+							Description: "The DN of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "The name of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"sam_account_name": {
+							Description: "The sAMAccountName of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"user_principal_name": {
+							Description: "The userPrincipalName of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"mail": {
+							Description: "The mail of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"description": {
+							Description: "The description of the user.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceLDAPUsersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	baseDN, err := client.baseDNOrDefault(d.Get("ou").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := buildListFilter("(objectCategory=person)(objectClass=user)", d.Get("name_prefix").(string), d.Get("filter").(string))
+
+	entries, err := client.search(baseDN, d.Get("scope").(int), filter, []string{"name", "sAMAccountName", "userPrincipalName", "mail", "description"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sortEntriesByDN(entries)
+
+	users := []interface{}{}
+	for _, entry := range entries {
+		users = append(users, map[string]interface{}{
+			"dn":                  entry.DN,
+			"name":                entry.GetAttributeValue("name"),
+			"sam_account_name":    entry.GetAttributeValue("sAMAccountName"),
+			"user_principal_name": entry.GetAttributeValue("userPrincipalName"),
+			"mail":                entry.GetAttributeValue("mail"),
+			"description":         entry.GetAttributeValue("description"),
+		})
+	}
+
+	d.SetId(baseDN + filter)
+
+	err = d.Set("users", users)
+
+	return diag.FromErr(err)
+}
@@ -0,0 +1,81 @@
+package ldap
+
+import "fmt"
+
+// accidentalDeletionACE is the deny ACE set on an object by ADUC
+// "Protect object from accidental deletion" option
+var accidentalDeletionACE = ace{
+	Type: aceTypeAccessDenied,
+	Mask: rightDelete | rightDSDeleteTree,
+	SID:  sidEveryone,
+}
+
+// readAccidentalDeletionProtection returns true if the object DACL contains
+// the "Protect object from accidental deletion" deny ACE
+func (c *providerClient) readAccidentalDeletionProtection(dn string) (bool, error) {
+	sd, err := c.readSecurityDescriptor(dn, sdFlagsDACL)
+	if err != nil {
+		return false, err
+	}
+
+	if sd.DACL == nil {
+		return false, nil
+	}
+
+	for _, entry := range sd.DACL.ACEs {
+		if entry.Raw == nil &&
+			entry.Type == accidentalDeletionACE.Type &&
+			entry.Flags&aceFlagInherited == 0 &&
+			entry.Mask&accidentalDeletionACE.Mask == accidentalDeletionACE.Mask &&
+			entry.SID == accidentalDeletionACE.SID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// setAccidentalDeletionProtection adds or removes the "Protect object from
+// accidental deletion" deny ACE on the object. Unlike ADUC, the deny
+// DELETE_CHILD ACE is not set on the parent, as it is not managed by the
+// resource and other objects may rely on it.
+func (c *providerClient) setAccidentalDeletionProtection(dn string, enabled bool) error {
+	return c.updateSecurityDescriptor(dn, sdFlagsDACL, func(sd *securityDescriptor) (bool, error) {
+		if sd.DACL == nil {
+			sd.DACL = &acl{}
+		}
+
+		if !enabled {
+			return sd.DACL.removeACE(accidentalDeletionACE), nil
+		}
+
+		if sd.DACL.hasACE(accidentalDeletionACE) {
+			return false, nil
+		}
+		sd.DACL.addACE(accidentalDeletionACE)
+
+		return true, nil
+	})
+}
+
+// deleteProtectedObject runs the deletion of an object, first removing the
+// "Protect object from accidental deletion" deny ACE if protected is true. The
+// ACE is put back if the deletion fails, so the object is never left unprotected.
+func (c *providerClient) deleteProtectedObject(dn string, protected bool, deleteObject func() error) error {
+	if !protected {
+		return deleteObject()
+	}
+
+	if err := c.setAccidentalDeletionProtection(dn, false); err != nil {
+		return err
+	}
+
+	if err := deleteObject(); err != nil {
+		if restoreErr := c.setAccidentalDeletionProtection(dn, true); restoreErr != nil {
+			return fmt.Errorf("%w (the accidental deletion protection couldn't be restored: %s)", err, restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// parentDN returns the DN of the parent of the given DN
+func parentDN(dn string) (string, error) {
+	parsedDN, err := ldap.ParseDN(dn)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing DN %q: %w", dn, err)
+	}
+
+	if len(parsedDN.RDNs) < 2 {
+		return "", fmt.Errorf("DN %q has no parent", dn)
+	}
+
+	return joinRDNs(parsedDN.RDNs[1:]), nil
+}
+
+// joinRDNs builds a DN string from a list of RDNs
+func joinRDNs(rdns []*ldap.RelativeDN) string {
+	parts := []string{}
+	for _, rdn := range rdns {
+		parts = append(parts, rdn.String())
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// dnEqualFold returns true if both DNs are equal, ignoring the case
+func dnEqualFold(a, b string) bool {
+	parsedA, errA := ldap.ParseDN(a)
+	parsedB, errB := ldap.ParseDN(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(a, b)
+	}
+
+	return parsedA.EqualFold(parsedB)
+}
+
+// keepConfiguredDNs returns the DN values read from the server, using the spelling of
+// the equal DNs of the configured set, as Active Directory returns DNs in its own case
+func keepConfiguredDNs(values []string, configured interface{}) []string {
+	result := []string{}
+	for _, value := range values {
+		for _, raw := range configured.(*schema.Set).List() {
+			if dnEqualFold(value, raw.(string)) {
+				value = raw.(string)
+				break
+			}
+		}
+		result = append(result, value)
+	}
+
+	return result
+}
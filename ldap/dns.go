@@ -0,0 +1,606 @@
+package ldap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// DNS record types
+const (
+	dnsTypeZero  uint16 = 0
+	dnsTypeA     uint16 = 1
+	dnsTypeNS    uint16 = 2
+	dnsTypeCNAME uint16 = 5
+	dnsTypeSOA   uint16 = 6
+	dnsTypePTR   uint16 = 12
+	dnsTypeTXT   uint16 = 16
+	dnsTypeAAAA  uint16 = 28
+	dnsTypeSRV   uint16 = 33
+)
+
+// dnsRecordTypes maps the record type names managed by ldap_dns_record to their type
+var dnsRecordTypes = map[string]uint16{
+	"A":     dnsTypeA,
+	"AAAA":  dnsTypeAAAA,
+	"CNAME": dnsTypeCNAME,
+	"PTR":   dnsTypePTR,
+	"SRV":   dnsTypeSRV,
+	"TXT":   dnsTypeTXT,
+}
+
+// dnsRecordVersion is the version of the dnsRecord structure
+const dnsRecordVersion = 5
+
+// dnsRankZone is the rank of the records of a zone the server is authoritative for
+const dnsRankZone = 0xF0
+
+// dnsRecordHeaderLength is the length of the dnsRecord structure before the record data
+const dnsRecordHeaderLength = 24
+
+// dnsApexName is the name of the dnsNode of the zone apex, holding the SOA and NS records
+const dnsApexName = "@"
+
+// dNSProperty ids
+const (
+	dnsPropertyZoneType    uint32 = 0x01
+	dnsPropertyAllowUpdate uint32 = 0x02
+)
+
+// dnsZoneTypePrimary is the DSPROPERTY_ZONE_TYPE value of primary zones
+const dnsZoneTypePrimary = 1
+
+// dnsAllowUpdates maps the dynamic update names to their DSPROPERTY_ZONE_ALLOW_UPDATE value
+var dnsAllowUpdates = map[string]byte{
+	"none":                 0,
+	"nonsecure_and_secure": 1,
+	"secure":               2,
+}
+
+// dnsSerialMutex serializes the SOA serial increments, as resources applied
+// in parallel may increment the serial of the same zone
+var dnsSerialMutex sync.Mutex
+
+// dnsRecord is a decoded dnsRecord attribute value
+type dnsRecord struct {
+	Type      uint16
+	Rank      byte
+	Flags     uint16
+	Serial    uint32
+	TTL       uint32
+	Timestamp uint32
+	Data      []byte
+}
+
+// dnsSOA is the decoded data of a SOA record
+type dnsSOA struct {
+	Serial            uint32
+	Refresh           uint32
+	Retry             uint32
+	Expire            uint32
+	MinimumTTL        uint32
+	PrimaryServer     string
+	ResponsiblePerson string
+}
+
+// dnsNode is a dnsNode object with its decoded records
+type dnsNode struct {
+	DN         string
+	Records    []dnsRecord
+	Raw        [][]byte
+	Tombstoned bool
+}
+
+// dnsRecordTypeNames returns the sorted record type names of the keys of dnsRecordTypes
+func dnsRecordTypeNames() []string {
+	names := []string{}
+	for name := range dnsRecordTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// dnsAllowUpdateNames returns the sorted dynamic update names of the keys of dnsAllowUpdates
+func dnsAllowUpdateNames() []string {
+	names := []string{}
+	for name := range dnsAllowUpdates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// decodeDNSRecord decodes a dnsRecord attribute value
+func decodeDNSRecord(b []byte) (dnsRecord, error) {
+	if len(b) < dnsRecordHeaderLength {
+		return dnsRecord{}, fmt.Errorf("DNS record too short: %d bytes", len(b))
+	}
+
+	dataLength := int(binary.LittleEndian.Uint16(b[0:2]))
+	if dnsRecordHeaderLength+dataLength > len(b) {
+		return dnsRecord{}, fmt.Errorf("invalid DNS record data length %d", dataLength)
+	}
+
+	if b[4] != dnsRecordVersion {
+		return dnsRecord{}, fmt.Errorf("unsupported DNS record version %d", b[4])
+	}
+
+	return dnsRecord{
+		Type:      binary.LittleEndian.Uint16(b[2:4]),
+		Rank:      b[5],
+		Flags:     binary.LittleEndian.Uint16(b[6:8]),
+		Serial:    binary.LittleEndian.Uint32(b[8:12]),
+		TTL:       binary.BigEndian.Uint32(b[12:16]),
+		Timestamp: binary.LittleEndian.Uint32(b[20:24]),
+		Data:      append([]byte{}, b[dnsRecordHeaderLength:dnsRecordHeaderLength+dataLength]...),
+	}, nil
+}
+
+// encode encodes the DNS record as a dnsRecord attribute value
+func (r dnsRecord) encode() []byte {
+	b := make([]byte, dnsRecordHeaderLength, dnsRecordHeaderLength+len(r.Data))
+	binary.LittleEndian.PutUint16(b[0:2], uint16(len(r.Data)))
+	binary.LittleEndian.PutUint16(b[2:4], r.Type)
+	b[4] = dnsRecordVersion
+	b[5] = r.Rank
+	binary.LittleEndian.PutUint16(b[6:8], r.Flags)
+	binary.LittleEndian.PutUint32(b[8:12], r.Serial)
+	// The TTL is the only header field stored in network byte order
+	binary.BigEndian.PutUint32(b[12:16], r.TTL)
+	binary.LittleEndian.PutUint32(b[20:24], r.Timestamp)
+
+	return append(b, r.Data...)
+}
+
+// encodeDNSName encodes a DNS name as a DNS_COUNT_NAME: the length of the raw
+// name, the number of labels, then the length prefixed labels ending with a 0
+func encodeDNSName(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+
+	raw := []byte{}
+	labels := 0
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid DNS name %q: labels must be 1 to 63 characters long", name)
+			}
+			raw = append(raw, byte(len(label)))
+			raw = append(raw, label...)
+			labels++
+		}
+	}
+	raw = append(raw, 0)
+
+	if len(raw) > 255 {
+		return nil, fmt.Errorf("invalid DNS name %q: too long", name)
+	}
+
+	return append([]byte{byte(len(raw)), byte(labels)}, raw...), nil
+}
+
+// decodeDNSName decodes the DNS_COUNT_NAME at the beginning of b, returning
+// the name without trailing dot and the number of bytes read
+func decodeDNSName(b []byte) (string, int, error) {
+	if len(b) < 2 || len(b) < 2+int(b[0]) {
+		return "", 0, fmt.Errorf("DNS name truncated")
+	}
+
+	raw := b[2 : 2+int(b[0])]
+	labels := []string{}
+	for i := 0; i < int(b[1]); i++ {
+		if len(raw) == 0 || len(raw) < 1+int(raw[0]) {
+			return "", 0, fmt.Errorf("DNS name label %d truncated", i)
+		}
+		labels = append(labels, string(raw[1:1+int(raw[0])]))
+		raw = raw[1+int(raw[0]):]
+	}
+
+	return strings.Join(labels, "."), 2 + int(b[0]), nil
+}
+
+// encodeDNSRecordData encodes a record value, as written in ldap_dns_record, to the record data
+func encodeDNSRecordData(recordType uint16, value string) ([]byte, error) {
+	switch recordType {
+	case dnsTypeA:
+		ip := net.ParseIP(value).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IPv4 address %q", value)
+		}
+		return ip, nil
+
+	case dnsTypeAAAA:
+		ip := net.ParseIP(value)
+		if ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("invalid IPv6 address %q", value)
+		}
+		return ip.To16(), nil
+
+	case dnsTypeNS, dnsTypeCNAME, dnsTypePTR:
+		return encodeDNSName(value)
+
+	case dnsTypeSRV:
+		fields := strings.Fields(value)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid SRV record %q, expected `<priority> <weight> <port> <target>`", value)
+		}
+
+		b := make([]byte, 6)
+		for i, field := range fields[:3] {
+			v, err := strconv.ParseUint(field, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SRV record %q: %w", value, err)
+			}
+			binary.BigEndian.PutUint16(b[2*i:], uint16(v))
+		}
+
+		target, err := encodeDNSName(fields[3])
+		if err != nil {
+			return nil, err
+		}
+
+		return append(b, target...), nil
+
+	case dnsTypeTXT:
+		// Strings longer than 255 bytes are split in several character strings
+		b := []byte{}
+		for len(value) > 255 {
+			b = append(b, 255)
+			b = append(b, value[:255]...)
+			value = value[255:]
+		}
+		b = append(b, byte(len(value)))
+
+		return append(b, value...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported DNS record type %d", recordType)
+}
+
+// decodeDNSRecordData decodes the record data to a record value, as written in ldap_dns_record
+func decodeDNSRecordData(recordType uint16, data []byte) (string, error) {
+	switch recordType {
+	case dnsTypeA:
+		if len(data) != net.IPv4len {
+			return "", fmt.Errorf("invalid A record length %d", len(data))
+		}
+		return net.IP(data).String(), nil
+
+	case dnsTypeAAAA:
+		if len(data) != net.IPv6len {
+			return "", fmt.Errorf("invalid AAAA record length %d", len(data))
+		}
+		return net.IP(data).String(), nil
+
+	case dnsTypeNS, dnsTypeCNAME, dnsTypePTR:
+		name, _, err := decodeDNSName(data)
+		return name, err
+
+	case dnsTypeSRV:
+		if len(data) < 6 {
+			return "", fmt.Errorf("SRV record truncated")
+		}
+
+		target, _, err := decodeDNSName(data[6:])
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%d %d %d %s",
+			binary.BigEndian.Uint16(data[0:2]),
+			binary.BigEndian.Uint16(data[2:4]),
+			binary.BigEndian.Uint16(data[4:6]),
+			target,
+		), nil
+
+	case dnsTypeTXT:
+		value := ""
+		for len(data) > 0 {
+			if len(data) < 1+int(data[0]) {
+				return "", fmt.Errorf("TXT record truncated")
+			}
+			value += string(data[1 : 1+int(data[0])])
+			data = data[1+int(data[0]):]
+		}
+		return value, nil
+	}
+
+	return "", fmt.Errorf("unsupported DNS record type %d", recordType)
+}
+
+// decodeDNSSOA decodes the data of a SOA record
+func decodeDNSSOA(data []byte) (dnsSOA, error) {
+	if len(data) < 20 {
+		return dnsSOA{}, fmt.Errorf("SOA record truncated")
+	}
+
+	soa := dnsSOA{
+		Serial:     binary.BigEndian.Uint32(data[0:4]),
+		Refresh:    binary.BigEndian.Uint32(data[4:8]),
+		Retry:      binary.BigEndian.Uint32(data[8:12]),
+		Expire:     binary.BigEndian.Uint32(data[12:16]),
+		MinimumTTL: binary.BigEndian.Uint32(data[16:20]),
+	}
+
+	primaryServer, n, err := decodeDNSName(data[20:])
+	if err != nil {
+		return soa, fmt.Errorf("failed decoding SOA primary server: %w", err)
+	}
+	soa.PrimaryServer = primaryServer
+
+	if soa.ResponsiblePerson, _, err = decodeDNSName(data[20+n:]); err != nil {
+		return soa, fmt.Errorf("failed decoding SOA responsible person: %w", err)
+	}
+
+	return soa, nil
+}
+
+// encode encodes the SOA record data
+func (s dnsSOA) encode() ([]byte, error) {
+	b := make([]byte, 20)
+	binary.BigEndian.PutUint32(b[0:4], s.Serial)
+	binary.BigEndian.PutUint32(b[4:8], s.Refresh)
+	binary.BigEndian.PutUint32(b[8:12], s.Retry)
+	binary.BigEndian.PutUint32(b[12:16], s.Expire)
+	binary.BigEndian.PutUint32(b[16:20], s.MinimumTTL)
+
+	for _, name := range []string{s.PrimaryServer, s.ResponsiblePerson} {
+		encoded, err := encodeDNSName(name)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, encoded...)
+	}
+
+	return b, nil
+}
+
+// dnsTombstoneRecord returns the record the DNS server stores in a tombstoned
+// dnsNode: a record of type 0 whose data is the FILETIME of the deletion
+func dnsTombstoneRecord(serial uint32) dnsRecord {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, uint64(time.Now().UnixNano()/100+fileTimeEpochOffset))
+
+	return dnsRecord{
+		Type:   dnsTypeZero,
+		Rank:   dnsRankZone,
+		Serial: serial,
+		Data:   data,
+	}
+}
+
+// encodeDNSProperty encodes a dNSProperty attribute value
+func encodeDNSProperty(id uint32, data []byte) []byte {
+	b := make([]byte, 20, 21+len(data))
+	binary.LittleEndian.PutUint32(b[0:4], uint32(len(data)))
+	// The name length is unused and always 1
+	binary.LittleEndian.PutUint32(b[4:8], 1)
+	binary.LittleEndian.PutUint32(b[12:16], 1)
+	binary.LittleEndian.PutUint32(b[16:20], id)
+	b = append(b, data...)
+
+	return append(b, 0)
+}
+
+// decodeDNSProperty decodes a dNSProperty attribute value to its id and data
+func decodeDNSProperty(b []byte) (uint32, []byte, error) {
+	if len(b) < 20 {
+		return 0, nil, fmt.Errorf("DNS property too short: %d bytes", len(b))
+	}
+
+	dataLength := int(binary.LittleEndian.Uint32(b[0:4]))
+	if 20+dataLength > len(b) {
+		return 0, nil, fmt.Errorf("invalid DNS property data length %d", dataLength)
+	}
+
+	return binary.LittleEndian.Uint32(b[16:20]), b[20 : 20+dataLength], nil
+}
+
+// dnsZoneContainer returns the DN of the MicrosoftDNS container of the given partition:
+// `domain` and `forest` for the DomainDnsZones and ForestDnsZones application
+// partitions, `legacy` for the System container of the domain partition
+func (c *providerClient) dnsZoneContainer(partition string) (string, error) {
+	rootDSE, err := c.readRootDSE([]string{"defaultNamingContext", "rootDomainNamingContext"})
+	if err != nil {
+		return "", err
+	}
+
+	switch partition {
+	case "domain":
+		return "CN=MicrosoftDNS,DC=DomainDnsZones," + rootDSE.GetAttributeValue("defaultNamingContext"), nil
+	case "forest":
+		return "CN=MicrosoftDNS,DC=ForestDnsZones," + rootDSE.GetAttributeValue("rootDomainNamingContext"), nil
+	case "legacy":
+		return "CN=MicrosoftDNS,CN=System," + rootDSE.GetAttributeValue("defaultNamingContext"), nil
+	}
+
+	return "", fmt.Errorf("unknown DNS partition %q", partition)
+}
+
+// dnsZonePartition returns the partition name of the zone DN
+func dnsZonePartition(zoneDN string) string {
+	lowerDN := strings.ToLower(zoneDN)
+	switch {
+	case strings.Contains(lowerDN, ",dc=domaindnszones,"):
+		return "domain"
+	case strings.Contains(lowerDN, ",dc=forestdnszones,"):
+		return "forest"
+	}
+
+	return "legacy"
+}
+
+// dnsNodeDN returns the DN of the dnsNode or dnsZone object named name under
+// the given parent DN, escaping the name which may contain DN special characters
+func dnsNodeDN(name, parentDN string) string {
+	return fmt.Sprintf("DC=%s,%s", ldap.EscapeDN(name), parentDN)
+}
+
+// readDNSNode reads and decodes the records of the dnsNode at the given DN
+func (c *providerClient) readDNSNode(dn string) (*dnsNode, error) {
+	entry, err := c.readEntry(dn, []string{"dnsRecord", "dNSTombstoned"})
+	if err != nil {
+		return nil, err
+	}
+
+	node := &dnsNode{
+		DN:         entry.DN,
+		Raw:        entry.GetRawAttributeValues("dnsRecord"),
+		Tombstoned: strings.EqualFold(entry.GetAttributeValue("dNSTombstoned"), "TRUE"),
+	}
+
+	for _, raw := range node.Raw {
+		record, err := decodeDNSRecord(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding dnsRecord of %s: %w", dn, err)
+		}
+		node.Records = append(node.Records, record)
+	}
+
+	return node, nil
+}
+
+// readDNSSOA reads the SOA record of the zone, with its raw dnsRecord value.
+// A nil SOA is returned if the zone apex has no SOA record.
+func (c *providerClient) readDNSSOA(zoneDN string) (*dnsSOA, []byte, error) {
+	apexDN := dnsNodeDN(dnsApexName, zoneDN)
+
+	node, err := c.readDNSNode(apexDN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, record := range node.Records {
+		if record.Type == dnsTypeSOA {
+			soa, err := decodeDNSSOA(record.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed decoding SOA record of %s: %w", apexDN, err)
+			}
+			return &soa, node.Raw[i], nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// nextDNSSerial increments the SOA serial of the zone and returns it, so the
+// changed records can be stamped with it and picked up by zone transfers
+func (c *providerClient) nextDNSSerial(zoneDN string) (uint32, error) {
+	dnsSerialMutex.Lock()
+	defer dnsSerialMutex.Unlock()
+
+	soa, raw, err := c.readDNSSOA(zoneDN)
+	if err != nil {
+		return 0, err
+	}
+	if soa == nil {
+		return 0, fmt.Errorf("zone %s has no SOA record", zoneDN)
+	}
+
+	record, err := decodeDNSRecord(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	// Serials wrap around, skipping 0
+	soa.Serial++
+	if soa.Serial == 0 {
+		soa.Serial = 1
+	}
+
+	if record.Data, err = soa.encode(); err != nil {
+		return 0, err
+	}
+	record.Serial = soa.Serial
+
+	// Deleting the old value makes the request fail instead of overwriting
+	// a SOA record changed by the DNS server in the meantime
+	req := ldap.NewModifyRequest(dnsNodeDN(dnsApexName, zoneDN), nil)
+	req.Delete("dnsRecord", []string{string(raw)})
+	req.Add("dnsRecord", []string{string(record.encode())})
+
+	if err := c.Conn.Modify(req); err != nil {
+		return 0, fmt.Errorf("failed updating SOA serial of %s: %w", zoneDN, err)
+	}
+
+	return soa.Serial, nil
+}
+
+// updateDNSRecords replaces the records of the given type of the dnsNode, leaving the
+// records of the other types untouched. The node is created if it doesn't exist, and
+// revived if it is tombstoned. Like the DNS server does, the node is tombstoned instead
+// of deleted when its last records are removed, so the deletion is replicated to the
+// DNS servers.
+func (c *providerClient) updateDNSRecords(dn string, recordType uint16, records []dnsRecord, serial uint32) error {
+	values := []string{}
+	for _, record := range records {
+		values = append(values, string(record.encode()))
+	}
+
+	node, err := c.readDNSNode(dn)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		if len(values) == 0 {
+			return nil
+		}
+
+		req := ldap.NewAddRequest(dn, nil)
+		req.Attribute("objectClass", []string{"dnsNode"})
+		req.Attribute("dnsRecord", values)
+
+		return c.Conn.Add(req)
+	}
+	if err != nil {
+		return err
+	}
+
+	req := ldap.NewModifyRequest(dn, nil)
+
+	if node.Tombstoned {
+		if len(values) == 0 {
+			return nil
+		}
+
+		req.Replace("dnsRecord", values)
+		req.Replace("dNSTombstoned", []string{"FALSE"})
+
+		return c.Conn.Modify(req)
+	}
+
+	old := []string{}
+	others := 0
+	for i, record := range node.Records {
+		if record.Type == recordType {
+			old = append(old, string(node.Raw[i]))
+		} else {
+			others++
+		}
+	}
+
+	if len(values) == 0 && others == 0 {
+		req.Replace("dnsRecord", []string{string(dnsTombstoneRecord(serial).encode())})
+		req.Replace("dNSTombstoned", []string{"TRUE"})
+
+		return c.Conn.Modify(req)
+	}
+
+	if len(old) > 0 {
+		req.Delete("dnsRecord", old)
+	}
+	if len(values) > 0 {
+		req.Add("dnsRecord", values)
+	}
+	if len(req.Changes) == 0 {
+		return nil
+	}
+
+	return c.Conn.Modify(req)
+}
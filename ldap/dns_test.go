@@ -0,0 +1,276 @@
+package ldap
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDNSRecord(t *testing.T) {
+	cases := []struct {
+		name   string
+		hex    string
+		record dnsRecord
+	}{
+		{
+			name: "A record",
+			// Data length 4, type A, version 5, rank zone, no flags, serial 5,
+			// TTL 3600 in network byte order, reserved, static (no timestamp)
+			hex:    "0400 0100 05 f0 0000 05000000 00000e10 00000000 00000000 0a000001",
+			record: dnsRecord{Type: dnsTypeA, Rank: dnsRankZone, Serial: 5, TTL: 3600, Data: []byte{10, 0, 0, 1}},
+		},
+		{
+			name: "dynamic record with timestamp",
+			// Timestamp 3705780 hours since 1601-01-01 (2023-10-03 12:00 UTC), TTL 1200
+			hex:    "0400 0100 05 f0 0000 2a000000 000004b0 00000000 b48b3800 c0a80a14",
+			record: dnsRecord{Type: dnsTypeA, Rank: dnsRankZone, Serial: 42, TTL: 1200, Timestamp: 3705780, Data: []byte{192, 168, 10, 20}},
+		},
+		{
+			name:   "tombstone record",
+			hex:    "0800 0000 05 f0 0000 07000000 00000000 00000000 00000000 00c0b7c3e4f3d901",
+			record: dnsRecord{Type: dnsTypeZero, Rank: dnsRankZone, Serial: 7, Data: mustDecodeHex(t, "00c0b7c3e4f3d901")},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			record, err := decodeDNSRecord(b)
+			if err != nil {
+				t.Fatalf("decodeDNSRecord: %s", err)
+			}
+			if !reflect.DeepEqual(record, c.record) {
+				t.Errorf("decodeDNSRecord: got %+v, want %+v", record, c.record)
+			}
+
+			if encoded := c.record.encode(); !bytes.Equal(encoded, b) {
+				t.Errorf("encode: got %x, want %x", encoded, b)
+			}
+		})
+	}
+}
+
+func TestDNSRecordInvalid(t *testing.T) {
+	cases := map[string]string{
+		"too short":   "0400 0100 05 f0 0000 05000000",
+		"data length": "0800 0100 05 f0 0000 05000000 00000e10 00000000 00000000 0a000001",
+		"version":     "0400 0100 04 f0 0000 05000000 00000e10 00000000 00000000 0a000001",
+	}
+
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeDNSRecord(mustDecodeHex(t, value)); err == nil {
+				t.Errorf("decodeDNSRecord: expected an error")
+			}
+		})
+	}
+}
+
+func TestDNSRecordData(t *testing.T) {
+	longTXT := strings.Repeat("a", 300)
+
+	cases := []struct {
+		name       string
+		recordType uint16
+		value      string
+		hex        string
+	}{
+		{"A", dnsTypeA, "10.0.0.1", "0a000001"},
+		{"AAAA", dnsTypeAAAA, "2001:db8::1", "20010db8000000000000000000000001"},
+		// DNS_COUNT_NAME: raw length 16, 3 labels, then the length prefixed labels
+		{"CNAME", dnsTypeCNAME, "web.domain.tld", "10 03 03776562 06646f6d61696e 03746c64 00"},
+		{"PTR", dnsTypePTR, "dc1.domain.tld", "10 03 03646331 06646f6d61696e 03746c64 00"},
+		{"NS", dnsTypeNS, "dc1.domain.tld", "10 03 03646331 06646f6d61696e 03746c64 00"},
+		// Priority 0, weight 100, port 389 in network byte order, then the target
+		{"SRV", dnsTypeSRV, "0 100 389 dc1.domain.tld", "0000 0064 0185 10 03 03646331 06646f6d61696e 03746c64 00"},
+		{"TXT", dnsTypeTXT, "v=spf1 -all", "0b 763d73706631202d616c6c"},
+		{"TXT longer than 255 bytes", dnsTypeTXT, longTXT, "ff" + strings.Repeat("61", 255) + "2d" + strings.Repeat("61", 45)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			data, err := encodeDNSRecordData(c.recordType, c.value)
+			if err != nil {
+				t.Fatalf("encodeDNSRecordData: %s", err)
+			}
+			if !bytes.Equal(data, b) {
+				t.Errorf("encodeDNSRecordData: got %x, want %x", data, b)
+			}
+
+			value, err := decodeDNSRecordData(c.recordType, b)
+			if err != nil {
+				t.Fatalf("decodeDNSRecordData: %s", err)
+			}
+			if value != c.value {
+				t.Errorf("decodeDNSRecordData: got %q, want %q", value, c.value)
+			}
+		})
+	}
+}
+
+func TestDNSRecordDataInvalid(t *testing.T) {
+	cases := []struct {
+		recordType uint16
+		value      string
+	}{
+		{dnsTypeA, "2001:db8::1"},
+		{dnsTypeA, "10.0.0"},
+		{dnsTypeAAAA, "10.0.0.1"},
+		{dnsTypeCNAME, "web..domain.tld"},
+		{dnsTypeCNAME, strings.Repeat("a", 64) + ".domain.tld"},
+		{dnsTypeSRV, "0 100 dc1.domain.tld"},
+		{dnsTypeSRV, "0 100 65536 dc1.domain.tld"},
+	}
+
+	for _, c := range cases {
+		if _, err := encodeDNSRecordData(c.recordType, c.value); err == nil {
+			t.Errorf("encodeDNSRecordData(%d, %q): expected an error", c.recordType, c.value)
+		}
+	}
+}
+
+func TestDNSName(t *testing.T) {
+	cases := map[string]string{
+		"":               "01 00 00",
+		"tld":            "05 01 03746c64 00",
+		"domain.tld":     "0c 02 06646f6d61696e 03746c64 00",
+		"web.domain.tld": "10 03 03776562 06646f6d61696e 03746c64 00",
+	}
+
+	for name, value := range cases {
+		b := mustDecodeHex(t, value)
+
+		for _, input := range []string{name, name + "."} {
+			encoded, err := encodeDNSName(input)
+			if err != nil {
+				t.Fatalf("encodeDNSName(%q): %s", input, err)
+			}
+			if !bytes.Equal(encoded, b) {
+				t.Errorf("encodeDNSName(%q): got %x, want %x", input, encoded, b)
+			}
+		}
+
+		// Trailing bytes are left to the caller
+		decoded, n, err := decodeDNSName(append(b, 0xff))
+		if err != nil {
+			t.Fatalf("decodeDNSName(%x): %s", b, err)
+		}
+		if decoded != name || n != len(b) {
+			t.Errorf("decodeDNSName(%x): got %q and %d bytes, want %q and %d bytes", b, decoded, n, name, len(b))
+		}
+	}
+}
+
+func TestDNSSOA(t *testing.T) {
+	b := mustDecodeHex(t, ""+
+		// Serial 1, refresh 900, retry 600, expire 86400, minimum TTL 3600
+		"00000001 00000384 00000258 00015180 00000e10"+
+		// Primary server dc1.domain.tld
+		"10 03 03646331 06646f6d61696e 03746c64 00"+
+		// Responsible person hostmaster.domain.tld
+		"17 03 0a686f73746d6173746572 06646f6d61696e 03746c64 00")
+
+	soa := dnsSOA{
+		Serial:            1,
+		Refresh:           dnsZoneRefresh,
+		Retry:             dnsZoneRetry,
+		Expire:            dnsZoneExpire,
+		MinimumTTL:        dnsZoneMinimumTTL,
+		PrimaryServer:     "dc1.domain.tld",
+		ResponsiblePerson: "hostmaster.domain.tld",
+	}
+
+	decoded, err := decodeDNSSOA(b)
+	if err != nil {
+		t.Fatalf("decodeDNSSOA: %s", err)
+	}
+	if decoded != soa {
+		t.Errorf("decodeDNSSOA: got %+v, want %+v", decoded, soa)
+	}
+
+	encoded, err := soa.encode()
+	if err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+	if !bytes.Equal(encoded, b) {
+		t.Errorf("encode: got %x, want %x", encoded, b)
+	}
+
+	if _, err := decodeDNSSOA(b[:30]); err == nil {
+		t.Errorf("decodeDNSSOA: expected an error for a truncated SOA")
+	}
+}
+
+func TestDNSProperty(t *testing.T) {
+	cases := []struct {
+		name string
+		id   uint32
+		data []byte
+		hex  string
+	}{
+		// Data length, name length 1, flag, version 1, id, data, then the unused name
+		{"zone type", dnsPropertyZoneType, []byte{1, 0, 0, 0}, "04000000 01000000 00000000 01000000 01000000 01000000 00"},
+		{"allow update", dnsPropertyAllowUpdate, []byte{dnsAllowUpdates["secure"]}, "01000000 01000000 00000000 01000000 02000000 02 00"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			if encoded := encodeDNSProperty(c.id, c.data); !bytes.Equal(encoded, b) {
+				t.Errorf("encodeDNSProperty: got %x, want %x", encoded, b)
+			}
+
+			id, data, err := decodeDNSProperty(b)
+			if err != nil {
+				t.Fatalf("decodeDNSProperty: %s", err)
+			}
+			if id != c.id || !bytes.Equal(data, c.data) {
+				t.Errorf("decodeDNSProperty: got %d and %x, want %d and %x", id, data, c.id, c.data)
+			}
+		})
+	}
+
+	if _, _, err := decodeDNSProperty(mustDecodeHex(t, "08000000 01000000 00000000 01000000 01000000 01000000 00")); err == nil {
+		t.Errorf("decodeDNSProperty: expected an error for an invalid data length")
+	}
+}
+
+func TestDNSNodeDN(t *testing.T) {
+	const zoneDN = "DC=domain.tld,CN=MicrosoftDNS,DC=DomainDnsZones,DC=domain,DC=tld"
+
+	cases := map[string]string{
+		dnsApexName:  "DC=@," + zoneDN,
+		"web":        "DC=web," + zoneDN,
+		"_ldap._tcp": "DC=_ldap._tcp," + zoneDN,
+		"a,b":        `DC=a\,b,` + zoneDN,
+		"a+b":        `DC=a\+b,` + zoneDN,
+		`a\b`:        `DC=a\\b,` + zoneDN,
+		"#web":       `DC=\#web,` + zoneDN,
+	}
+
+	for name, want := range cases {
+		if dn := dnsNodeDN(name, zoneDN); dn != want {
+			t.Errorf("dnsNodeDN(%q): got %s, want %s", name, dn, want)
+		}
+	}
+}
+
+func TestDNSZonePartition(t *testing.T) {
+	cases := map[string]string{
+		"DC=domain.tld,CN=MicrosoftDNS,DC=DomainDnsZones,DC=domain,DC=tld":        "domain",
+		"DC=_msdcs.domain.tld,CN=MicrosoftDNS,DC=ForestDnsZones,DC=domain,DC=tld": "forest",
+		"DC=domain.tld,CN=MicrosoftDNS,CN=System,DC=domain,DC=tld":                "legacy",
+		"dc=domain.tld,cn=MicrosoftDNS,dc=domaindnszones,dc=domain,dc=tld":        "domain",
+	}
+
+	for dn, want := range cases {
+		if partition := dnsZonePartition(dn); partition != want {
+			t.Errorf("dnsZonePartition(%q): got %s, want %s", dn, partition, want)
+		}
+	}
+}
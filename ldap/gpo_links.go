@@ -0,0 +1,247 @@
+package ldap
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// gPLink link options
+const (
+	gpLinkDisabled = 0x1
+	gpLinkEnforced = 0x2
+)
+
+// gPOptions flags
+const (
+	gpOptionsBlockInheritance = 0x1
+)
+
+// gpLinkRegexp matches a link of the gPLink attribute: [LDAP://<GPO DN>;<options>]
+var gpLinkRegexp = regexp.MustCompile(`(?i)\[LDAP://([^;\]]+);(\d+)\]`)
+
+// gpoLink is a Group Policy link of an OU
+type gpoLink struct {
+	GPO      string
+	Enforced bool
+	Disabled bool
+}
+
+// gpoLinksResourceSchema returns the schema of the GPO links managed by a resource
+func gpoLinksResourceSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Description:      fmt.Sprintf("Group Policy links of the LDAP %s, by link order: the first link has the highest precedence. All the links are removed if not set, unless `gPLink` is in `ignore_attributes`.", kind),
+		Type:             schema.TypeList,
+		Optional:         true,
+		DiffSuppressFunc: ignoredAttributeDiffSuppress("gPLink"),
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"gpo": {
+					Description:      "The DN or the GUID of the GPO.",
+					Type:             schema.TypeString,
+					Required:         true,
+					DiffSuppressFunc: gpoDiffSuppress,
+				},
+				"enforced": {
+					Description:      "Whether the link is enforced. Default is `false`.",
+					Type:             schema.TypeBool,
+					Optional:         true,
+					Default:          false,
+					DiffSuppressFunc: ignoredAttributeDiffSuppress("gPLink"),
+				},
+				"disabled": {
+					Description:      "Whether the link is disabled. Default is `false`.",
+					Type:             schema.TypeBool,
+					Optional:         true,
+					Default:          false,
+					DiffSuppressFunc: ignoredAttributeDiffSuppress("gPLink"),
+				},
+			},
+		},
+	}
+}
+
+// gpoLinksDataSourceSchema returns the schema of the GPO links returned by a data source
+func gpoLinksDataSourceSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Group Policy links of the LDAP %s, by link order: the first link has the highest precedence.", kind),
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"gpo": {
+					Description: "The DN of the GPO.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"enforced": {
+					Description: "Whether the link is enforced.",
+					Type:        schema.TypeBool,
+					Computed:    true,
+				},
+				"disabled": {
+					Description: "Whether the link is disabled.",
+					Type:        schema.TypeBool,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// gpoGUID returns the lowercase GUID of a GPO given by DN or GUID,
+// or an empty string if it can't be found
+func gpoGUID(gpo string) string {
+	if isGUID(gpo) {
+		return strings.ToLower(strings.Trim(gpo, "{}"))
+	}
+
+	parsedDN, err := ldap.ParseDN(gpo)
+	if err != nil || len(parsedDN.RDNs) == 0 || len(parsedDN.RDNs[0].Attributes) != 1 {
+		return ""
+	}
+
+	guid := parsedDN.RDNs[0].Attributes[0].Value
+	if !isGUID(guid) {
+		return ""
+	}
+
+	return strings.ToLower(strings.Trim(guid, "{}"))
+}
+
+// gpoDiffSuppress ignores the differences between a GPO DN and GUID of the same GPO
+func gpoDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	if isIgnoredAttribute(d, "gPLink") {
+		return true
+	}
+
+	guid := gpoGUID(old)
+
+	return guid != "" && guid == gpoGUID(new)
+}
+
+// parseGPLink parses a gPLink attribute value to the links, by link order.
+// The last link of gPLink has the highest precedence.
+func parseGPLink(value string) ([]gpoLink, error) {
+	links := []gpoLink{}
+
+	for _, match := range gpLinkRegexp.FindAllStringSubmatch(value, -1) {
+		options, err := strconv.Atoi(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid gPLink options %q: %w", match[2], err)
+		}
+
+		links = append([]gpoLink{{
+			GPO:      match[1],
+			Enforced: options&gpLinkEnforced != 0,
+			Disabled: options&gpLinkDisabled != 0,
+		}}, links...)
+	}
+
+	return links, nil
+}
+
+// formatGPLink formats the links, by link order, to a gPLink attribute value
+func formatGPLink(links []gpoLink) string {
+	value := ""
+	for _, link := range links {
+		options := 0
+		if link.Enforced {
+			options |= gpLinkEnforced
+		}
+		if link.Disabled {
+			options |= gpLinkDisabled
+		}
+
+		value = fmt.Sprintf("[LDAP://%s;%d]", link.GPO, options) + value
+	}
+
+	return value
+}
+
+// expandGPOLinks converts the GPO links list to links, resolving the GPOs given
+// by GUID to their DN in the Policies container of the domain
+func (c *providerClient) expandGPOLinks(v interface{}) ([]gpoLink, error) {
+	links := []gpoLink{}
+
+	for _, raw := range v.([]interface{}) {
+		link := raw.(map[string]interface{})
+
+		gpo := link["gpo"].(string)
+		if isGUID(gpo) {
+			baseDN, err := c.baseDNOrDefault("")
+			if err != nil {
+				return nil, err
+			}
+			gpo = fmt.Sprintf("CN={%s},CN=Policies,CN=System,%s", strings.ToUpper(gpoGUID(gpo)), baseDN)
+		}
+
+		links = append(links, gpoLink{
+			GPO:      gpo,
+			Enforced: link["enforced"].(bool),
+			Disabled: link["disabled"].(bool),
+		})
+	}
+
+	return links, nil
+}
+
+// flattenGPOLinks converts links to the GPO links list
+func flattenGPOLinks(links []gpoLink) []interface{} {
+	result := []interface{}{}
+	for _, link := range links {
+		result = append(result, map[string]interface{}{
+			"gpo":      link.GPO,
+			"enforced": link.Enforced,
+			"disabled": link.Disabled,
+		})
+	}
+
+	return result
+}
+
+// readGPOLinks reads the GPO links and the block inheritance flag of the entry
+func (c *providerClient) readGPOLinks(dn string) ([]gpoLink, bool, error) {
+	entry, err := c.readEntry(dn, []string{"gPLink", "gPOptions"})
+	if err != nil {
+		return nil, false, err
+	}
+
+	links, err := parseGPLink(entry.GetAttributeValue("gPLink"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed parsing gPLink of %q: %w", dn, err)
+	}
+
+	gpOptions := 0
+	if value := entry.GetAttributeValue("gPOptions"); value != "" {
+		if gpOptions, err = strconv.Atoi(value); err != nil {
+			return nil, false, fmt.Errorf("invalid gPOptions %q of %q: %w", value, dn, err)
+		}
+	}
+
+	return links, gpOptions&gpOptionsBlockInheritance != 0, nil
+}
+
+// updateGPOLinks replaces the GPO links of the entry, removing gPLink when there are none
+func (c *providerClient) updateGPOLinks(dn string, links []gpoLink) error {
+	values := []string{}
+	if len(links) > 0 {
+		values = append(values, formatGPLink(links))
+	}
+
+	return c.replaceAttribute(dn, "gPLink", values)
+}
+
+// updateBlockInheritance sets the block inheritance flag of the entry
+func (c *providerClient) updateBlockInheritance(dn string, blockInheritance bool) error {
+	gpOptions := "0"
+	if blockInheritance {
+		gpOptions = strconv.Itoa(gpOptionsBlockInheritance)
+	}
+
+	return c.replaceAttribute(dn, "gPOptions", []string{gpOptions})
+}
@@ -0,0 +1,93 @@
+package ldap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGPLink(t *testing.T) {
+	const (
+		defaultDomainPolicy = "cn={31B2F340-016D-11D2-945F-00C04FB984F9},cn=policies,cn=system,DC=domain,DC=tld"
+		workstations        = "cn={6AC1786C-016F-11D2-945F-00C04FB984F9},cn=policies,cn=system,DC=domain,DC=tld"
+	)
+
+	cases := []struct {
+		name  string
+		value string
+		links []gpoLink
+	}{
+		{"empty", "", []gpoLink{}},
+		{
+			"single link",
+			"[LDAP://" + defaultDomainPolicy + ";0]",
+			[]gpoLink{{GPO: defaultDomainPolicy}},
+		},
+		{
+			// The last link of gPLink has the link order 1
+			"link order and options",
+			"[LDAP://" + workstations + ";1][LDAP://" + defaultDomainPolicy + ";2]",
+			[]gpoLink{
+				{GPO: defaultDomainPolicy, Enforced: true},
+				{GPO: workstations, Disabled: true},
+			},
+		},
+		{
+			"enforced and disabled",
+			"[LDAP://" + workstations + ";3]",
+			[]gpoLink{{GPO: workstations, Enforced: true, Disabled: true}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			links, err := parseGPLink(c.value)
+			if err != nil {
+				t.Fatalf("parseGPLink: %s", err)
+			}
+			if !reflect.DeepEqual(links, c.links) {
+				t.Errorf("parseGPLink: got %+v, want %+v", links, c.links)
+			}
+
+			if value := formatGPLink(c.links); value != c.value {
+				t.Errorf("formatGPLink: got %q, want %q", value, c.value)
+			}
+		})
+	}
+}
+
+func TestParseGPLinkLenient(t *testing.T) {
+	// Windows writes the gPLink of an OU whose links were all removed as a single space,
+	// and the LDAP prefix case varies between the tools
+	cases := map[string][]gpoLink{
+		" ": {},
+		"[ldap://cn={31B2F340-016D-11D2-945F-00C04FB984F9},cn=policies,cn=system,DC=domain,DC=tld;0]": {
+			{GPO: "cn={31B2F340-016D-11D2-945F-00C04FB984F9},cn=policies,cn=system,DC=domain,DC=tld"},
+		},
+	}
+
+	for value, want := range cases {
+		links, err := parseGPLink(value)
+		if err != nil {
+			t.Fatalf("parseGPLink(%q): %s", value, err)
+		}
+		if !reflect.DeepEqual(links, want) {
+			t.Errorf("parseGPLink(%q): got %+v, want %+v", value, links, want)
+		}
+	}
+}
+
+func TestGPOGUID(t *testing.T) {
+	cases := map[string]string{
+		"{31B2F340-016D-11D2-945F-00C04FB984F9}":                                           "31b2f340-016d-11d2-945f-00c04fb984f9",
+		"31b2f340-016d-11d2-945f-00c04fb984f9":                                             "31b2f340-016d-11d2-945f-00c04fb984f9",
+		"CN={31B2F340-016D-11D2-945F-00C04FB984F9},CN=Policies,CN=System,DC=domain,DC=tld": "31b2f340-016d-11d2-945f-00c04fb984f9",
+		"CN=Default Domain Policy,CN=Policies,CN=System,DC=domain,DC=tld":                  "",
+		"not a DN": "",
+	}
+
+	for gpo, want := range cases {
+		if guid := gpoGUID(gpo); guid != want {
+			t.Errorf("gpoGUID(%q): got %q, want %q", gpo, guid, want)
+		}
+	}
+}
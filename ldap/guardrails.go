@@ -0,0 +1,106 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// parseDNList parses a list of DN strings from the provider configuration
+func parseDNList(values []interface{}) ([]*ldap.DN, error) {
+	dns := []*ldap.DN{}
+	for _, value := range values {
+		dn, err := ldap.ParseDN(value.(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing DN %q: %w", value.(string), err)
+		}
+		dns = append(dns, dn)
+	}
+
+	return dns, nil
+}
+
+// checkWriteAllowed returns an error if the provider configuration forbids
+// any write (create, update, move or delete) on the given DN
+func (c *providerClient) checkWriteAllowed(dn string) error {
+	parsedDN, err := ldap.ParseDN(dn)
+	if err != nil {
+		return fmt.Errorf("failed parsing DN %q: %w", dn, err)
+	}
+
+	for _, protectedDN := range c.protectedDNs {
+		if protectedDN.EqualFold(parsedDN) {
+			return fmt.Errorf("DN %q is protected by the provider configuration (protected_dns)", dn)
+		}
+	}
+
+	// No allowed base DN configured means no restriction
+	if len(c.allowedBaseDNs) == 0 {
+		return nil
+	}
+
+	for _, baseDN := range c.allowedBaseDNs {
+		if baseDN.EqualFold(parsedDN) || baseDN.AncestorOfFold(parsedDN) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("DN %q is outside of the allowed base DNs (allowed_base_dns)", dn)
+}
+
+// checkSubtreeDeleteAllowed returns an error if the provider configuration
+// forbids deleting the given DN and all the objects below it
+func (c *providerClient) checkSubtreeDeleteAllowed(dn string) error {
+	if err := c.checkWriteAllowed(dn); err != nil {
+		return err
+	}
+
+	parsedDN, err := ldap.ParseDN(dn)
+	if err != nil {
+		return fmt.Errorf("failed parsing DN %q: %w", dn, err)
+	}
+
+	for _, protectedDN := range c.protectedDNs {
+		if parsedDN.AncestorOfFold(protectedDN) {
+			return fmt.Errorf("subtree of DN %q contains DN %q protected by the provider configuration (protected_dns)", dn, protectedDN.String())
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffCheckWriteAllowed returns a CustomizeDiffFunc rejecting at plan time
+// any change on an object whose DN, built as `<rdnType>=<name>,<ou>`, is forbidden
+// by the provider configuration. When the DN changes, the old DN is checked too
+// as the object will be deleted from there.
+func customizeDiffCheckWriteAllowed(rdnType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		client, ok := m.(*providerClient)
+		if !ok || client == nil {
+			return nil
+		}
+
+		// Nothing will be written if the plan is empty
+		if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+			return nil
+		}
+
+		// DN can't be checked before apply if it depends on unknown values
+		if !d.NewValueKnown("name") || !d.NewValueKnown("ou") {
+			return nil
+		}
+
+		dn := fmt.Sprintf("%s=%s,%s", rdnType, d.Get("name").(string), d.Get("ou").(string))
+		if err := client.checkWriteAllowed(dn); err != nil {
+			return err
+		}
+
+		if d.Id() != "" && d.Id() != dn {
+			return client.checkWriteAllowed(d.Id())
+		}
+
+		return nil
+	}
+}
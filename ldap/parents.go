@@ -0,0 +1,171 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// missingParentOUs returns the DNs of the missing OUs in the path of the given
+// OU DN, from the bottom to the top
+func (c *providerClient) missingParentOUs(ou string) ([]string, error) {
+	parsedOU, err := ldap.ParseDN(ou)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing DN %q: %w", ou, err)
+	}
+
+	// Find the missing OUs from the bottom up to the first existing entry
+	missing := []string{}
+	for i := range parsedOU.RDNs {
+		dn := joinRDNs(parsedOU.RDNs[i:])
+
+		exists, err := c.entryExists(dn)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			break
+		}
+
+		rdn := parsedOU.RDNs[i]
+		if len(rdn.Attributes) != 1 || !strings.EqualFold(rdn.Attributes[0].Type, "OU") {
+			return nil, fmt.Errorf("parent %q doesn't exist and can't be created as it isn't an OU", dn)
+		}
+
+		missing = append(missing, dn)
+	}
+
+	return missing, nil
+}
+
+// createParentOUs creates the missing OUs in the path of the given OU DN,
+// from the top to the bottom, and returns the DNs of the created OUs
+func (c *providerClient) createParentOUs(ou string) ([]string, error) {
+	missing, err := c.missingParentOUs(ou)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the missing OUs from the top to the bottom
+	created := []string{}
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := c.checkWriteAllowed(missing[i]); err != nil {
+			return created, err
+		}
+
+		if err := c.CreateOrganizationalUnit(missing[i], "", ""); err != nil {
+			return created, fmt.Errorf("failed creating parent OU %q: %w", missing[i], err)
+		}
+
+		created = append(created, missing[i])
+	}
+
+	return created, nil
+}
+
+// deleteCreatedParentOUs deletes the OUs previously created by createParentOUs,
+// from the bottom to the top, keeping the ones which aren't empty
+func (c *providerClient) deleteCreatedParentOUs(created []string) error {
+	for i := len(created) - 1; i >= 0; i-- {
+		dn := created[i]
+
+		exists, err := c.entryExists(dn)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		children, err := c.searchChildrenDNs(dn)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			continue
+		}
+
+		if err := c.checkWriteAllowed(dn); err != nil {
+			return err
+		}
+
+		if err := c.DeleteOrganizationalUnit(dn); err != nil {
+			return fmt.Errorf("failed deleting parent OU %q: %w", dn, err)
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffCheckParentsWriteAllowed rejects at plan time the creation of the
+// missing parent OUs forbidden by the provider configuration when create_parents
+// is enabled, as customizeDiffCheckWriteAllowed only checks the DN of the object
+func customizeDiffCheckParentsWriteAllowed(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Parents are only created with the object
+	if !d.Get("create_parents").(bool) || (d.Id() != "" && !d.HasChange("ou")) {
+		return nil
+	}
+
+	if !d.NewValueKnown("ou") {
+		return nil
+	}
+
+	missing, err := client.missingParentOUs(d.Get("ou").(string))
+	if err != nil {
+		return err
+	}
+
+	for _, dn := range missing {
+		if err := client.checkWriteAllowed(dn); err != nil {
+			return fmt.Errorf("parent OU %q can't be created: %w", dn, err)
+		}
+	}
+
+	return nil
+}
+
+// createParents creates the missing parent OUs of the resource if create_parents is enabled
+func createParents(client *providerClient, d *schema.ResourceData) error {
+	if !d.Get("create_parents").(bool) {
+		return nil
+	}
+
+	created, err := client.createParentOUs(d.Get("ou").(string))
+	if err != nil {
+		// Don't leave behind the OUs created before the failure
+		if cleanupErr := client.deleteCreatedParentOUs(created); cleanupErr != nil {
+			return fmt.Errorf("%w (the created parent OUs couldn't be deleted: %s)", err, cleanupErr)
+		}
+		return err
+	}
+
+	return d.Set("created_parents", created)
+}
+
+// cleanupCreatedParents deletes the parent OUs created because of create_parents
+// when the creation of the resource entry failed, and returns the creation error
+func cleanupCreatedParents(client *providerClient, d *schema.ResourceData, err error) error {
+	if cleanupErr := deleteCreatedParents(client, d); cleanupErr != nil {
+		return fmt.Errorf("%w (the created parent OUs couldn't be deleted: %s)", err, cleanupErr)
+	}
+
+	return err
+}
+
+// deleteCreatedParents deletes the empty parent OUs created because of create_parents
+func deleteCreatedParents(client *providerClient, d *schema.ResourceData) error {
+	created := []string{}
+	for _, dn := range d.Get("created_parents").([]interface{}) {
+		created = append(created, dn.(string))
+	}
+
+	return client.deleteCreatedParentOUs(created)
+}
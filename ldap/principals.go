@@ -0,0 +1,125 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// principalsSecurityDescriptor returns the security descriptor granting the
+// given principals SIDs access, as used by the attributes listing principals
+// allowed to do something on behalf of an account (msDS-GroupMSAMembership,
+// msDS-AllowedToActOnBehalfOfOtherIdentity)
+func principalsSecurityDescriptor(sids []string) *securityDescriptor {
+	sd := &securityDescriptor{
+		Revision: 1,
+		Owner:    sidBuiltinAdministrators,
+		DACL:     &acl{Revision: 4, ACEs: []ace{}},
+	}
+
+	for _, sid := range sids {
+		sd.DACL.addACE(ace{
+			Type: aceTypeAccessAllowed,
+			Mask: rightFullControl,
+			SID:  sid,
+		})
+	}
+
+	return sd
+}
+
+// principalsSIDs returns the SIDs granted access by the explicit allow ACEs of a principals security descriptor
+func principalsSIDs(sd *securityDescriptor) []string {
+	sids := []string{}
+	if sd.DACL == nil {
+		return sids
+	}
+
+	for _, entry := range sd.DACL.ACEs {
+		if entry.Raw == nil && entry.Type == aceTypeAccessAllowed && entry.Flags&aceFlagInherited == 0 {
+			sids = append(sids, entry.SID)
+		}
+	}
+
+	return sids
+}
+
+// readSIDs returns the SIDs of the objects at the given DNs
+func (c *providerClient) readSIDs(dns []string) ([]string, error) {
+	sids := []string{}
+	for _, dn := range dns {
+		entry, err := c.readEntry(dn, []string{"objectSid"})
+		if err != nil {
+			return nil, fmt.Errorf("failed reading objectSid of %q: %w", dn, err)
+		}
+
+		sid, err := decodeSID(entry.GetRawAttributeValue("objectSid"))
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding objectSid of %q: %w", dn, err)
+		}
+		sids = append(sids, sid)
+	}
+
+	return sids, nil
+}
+
+// resolveSIDs returns the DNs of the objects with the given SIDs in the domain.
+// SIDs which can't be resolved, like the ones of other domains, are returned unchanged.
+func (c *providerClient) resolveSIDs(sids []string) ([]string, error) {
+	if len(sids) == 0 {
+		return []string{}, nil
+	}
+
+	baseDN, err := c.baseDNOrDefault("")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.searchBySIDs(baseDN, sids, []string{"objectSid"})
+	if err != nil {
+		return nil, err
+	}
+
+	dnsBySID := map[string]string{}
+	for _, entry := range entries {
+		sid, err := decodeSID(entry.GetRawAttributeValue("objectSid"))
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding objectSid of %q: %w", entry.DN, err)
+		}
+		dnsBySID[strings.ToUpper(sid)] = entry.DN
+	}
+
+	dns := []string{}
+	for _, sid := range sids {
+		if dn, ok := dnsBySID[strings.ToUpper(sid)]; ok {
+			dns = append(dns, dn)
+		} else {
+			dns = append(dns, sid)
+		}
+	}
+
+	return dns, nil
+}
+
+// encodePrincipals returns the encoded principals security descriptor granting the objects at the given DNs
+func (c *providerClient) encodePrincipals(dns []string) ([]byte, error) {
+	sids, err := c.readSIDs(dns)
+	if err != nil {
+		return nil, err
+	}
+
+	return principalsSecurityDescriptor(sids).encode()
+}
+
+// decodePrincipals returns the DNs of the principals granted by an encoded principals security descriptor
+func (c *providerClient) decodePrincipals(raw []byte) ([]string, error) {
+	if len(raw) == 0 {
+		return []string{}, nil
+	}
+
+	sd, err := decodeSecurityDescriptor(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.resolveSIDs(principalsSIDs(sd))
+}
@@ -1,11 +1,26 @@
 package ldap
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/Ouest-France/goldap"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// providerClient wraps the goldap client with the provider-level settings
+type providerClient struct {
+	*goldap.Client
+	allowedBaseDNs []*ldap.DN
+	protectedDNs   []*ldap.DN
+
+	// globalCatalog is connected on first use by searchGlobalCatalog
+	globalCatalog      *goldap.Client
+	globalCatalogMutex sync.Mutex
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -50,15 +65,63 @@ func Provider() *schema.Provider {
 				Default:     false,
 				Description: "Don't verify the server TLS certificate. Default is `false`.",
 			},
+			"global_catalog_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("LDAP_GLOBAL_CATALOG_HOST", nil),
+				Description: "Global Catalog host, used for the forest-wide searches. Defaults to `host`.",
+			},
+			"global_catalog_port": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("LDAP_GLOBAL_CATALOG_PORT", nil),
+				Description: "Global Catalog port, used for the forest-wide searches. Defaults to `3268`, or `3269` with `tls`.",
+			},
+			"allowed_base_dns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "List of base DNs under which the provider is allowed to create, update, move or delete objects. Default is empty (no restriction).",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"protected_dns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "List of DNs the provider must never create, update, move or delete. Default is empty.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"ldap_group": resourceLDAPGroup(),
-			"ldap_ou":    resourceLDAPOU(),
+			"ldap_acl_entry":               resourceLDAPACLEntry(),
+			"ldap_computer":                resourceLDAPComputer(),
+			"ldap_contact":                 resourceLDAPContact(),
+			"ldap_dns_record":              resourceLDAPDNSRecord(),
+			"ldap_dns_zone":                resourceLDAPDNSZone(),
+			"ldap_group":                   resourceLDAPGroup(),
+			"ldap_kerberos_delegation":     resourceLDAPKerberosDelegation(),
+			"ldap_managed_service_account": resourceLDAPManagedServiceAccount(),
+			"ldap_ou":                      resourceLDAPOU(),
+			"ldap_password_settings":       resourceLDAPPasswordSettings(),
+			"ldap_service_principal_name":  resourceLDAPServicePrincipalName(),
+			"ldap_site":                    resourceLDAPSite(),
+			"ldap_site_link":               resourceLDAPSiteLink(),
+			"ldap_subnet":                  resourceLDAPSubnet(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"ldap_group": dataSourceLDAPGroup(),
-			"ldap_user":  dataSourceLDAPUser(),
-			"ldap_ou":    dataSourceLDAPOU(),
+			"ldap_acl":           dataSourceLDAPACL(),
+			"ldap_computer":      dataSourceLDAPComputer(),
+			"ldap_domain":        dataSourceLDAPDomain(),
+			"ldap_group":         dataSourceLDAPGroup(),
+			"ldap_group_members": dataSourceLDAPGroupMembers(),
+			"ldap_groups":        dataSourceLDAPGroups(),
+			"ldap_user":          dataSourceLDAPUser(),
+			"ldap_user_groups":   dataSourceLDAPUserGroups(),
+			"ldap_users":         dataSourceLDAPUsers(),
+			"ldap_ou":            dataSourceLDAPOU(),
+			"ldap_root_dse":      dataSourceLDAPRootDSE(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -84,5 +147,40 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		client.Conn.Debug.Enable(true)
 	}
 
-	return client, nil
+	allowedBaseDNs, err := parseDNList(d.Get("allowed_base_dns").([]interface{}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed_base_dns: %w", err)
+	}
+
+	protectedDNs, err := parseDNList(d.Get("protected_dns").([]interface{}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid protected_dns: %w", err)
+	}
+
+	// The Global Catalog uses the same credentials and TLS settings
+	globalCatalog := &goldap.Client{
+		Host:         client.Host,
+		Port:         d.Get("global_catalog_port").(int),
+		BindUser:     client.BindUser,
+		BindPassword: client.BindPassword,
+		TLS:          client.TLS,
+		TLSCACert:    client.TLSCACert,
+		TLSInsecure:  client.TLSInsecure,
+	}
+	if host := d.Get("global_catalog_host").(string); host != "" {
+		globalCatalog.Host = host
+	}
+	if globalCatalog.Port == 0 {
+		globalCatalog.Port = 3268
+		if globalCatalog.TLS {
+			globalCatalog.Port = 3269
+		}
+	}
+
+	return &providerClient{
+		Client:         client,
+		allowedBaseDNs: allowedBaseDNs,
+		protectedDNs:   protectedDNs,
+		globalCatalog:  globalCatalog,
+	}, nil
 }
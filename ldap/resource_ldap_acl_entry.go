@@ -0,0 +1,372 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLDAPACLEntry() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_acl_entry` is a resource for managing an access control entry (ACE) in the DACL of an LDAP object, non-authoritatively.",
+		CreateContext: resourceLDAPACLEntryCreate,
+		ReadContext:   resourceLDAPACLEntryRead,
+		DeleteContext: resourceLDAPACLEntryDelete,
+		CustomizeDiff: resourceLDAPACLEntryCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPACLEntryImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP object and the SDDL string of the ACE, separated by `|`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"target_dn": {
+				Description: "The DN of the LDAP object whose DACL contains the ACE.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"trustee": {
+				Description:      "The DN or SID of the trustee the ACE applies to.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: resolvedDiffSuppress("trustee_sid"),
+			},
+			"type": {
+				Description:  "The ACE type (`allow` or `deny`). Default is `allow`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "allow",
+				ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+			},
+			"rights": {
+				Description: "The access rights of the ACE.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(accessRightsNames(), false),
+				},
+			},
+			"object_type": {
+				Description:      "The object type the ACE applies to: a schema class or attribute lDAPDisplayName, an extended right, validated write or property set name or display name, or a GUID.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: resolvedDiffSuppress("object_type_guid"),
+			},
+			"inherited_object_type": {
+				Description:      "The schema class of the objects inheriting the ACE: an lDAPDisplayName or a GUID.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: resolvedDiffSuppress("inherited_object_type_guid"),
+			},
+			"inheritance": {
+				Description:  "The inheritance of the ACE (`none`, `all`, `descendents`, `self_and_children` or `children`). Default is `none`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice(aceInheritanceNames(), false),
+			},
+			"trustee_sid": {
+				Description: "The SID of the trustee.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"object_type_guid": {
+				Description: "The GUID of the object type.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"inherited_object_type_guid": {
+				Description: "The GUID of the inherited object type.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// resourceLDAPACLEntryCustomizeDiff rejects at plan time changes on an
+// object whose DN is forbidden by the provider configuration
+func resourceLDAPACLEntryCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Nothing will be written if the plan is empty
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("target_dn") {
+		return nil
+	}
+
+	return client.checkWriteAllowed(d.Get("target_dn").(string))
+}
+
+// resolvedDiffSuppress returns a DiffSuppressFunc ignoring the case changes of a
+// trustee or object type, and its replacement by the SID or GUID it was resolved to
+func resolvedDiffSuppress(resolvedKey string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if d.Id() == "" {
+			return false
+		}
+
+		return strings.EqualFold(old, new) || strings.EqualFold(strings.Trim(new, "{}"), d.Get(resolvedKey).(string))
+	}
+}
+
+// expandACLEntry builds the ACE of the resource from the resolved trustee SID and object type GUIDs
+func expandACLEntry(d *schema.ResourceData, trusteeSID, objectTypeGUID, inheritedObjectTypeGUID string) ace {
+	entry := ace{
+		Type:                aceTypeAccessAllowed,
+		Flags:               aceInheritances[d.Get("inheritance").(string)],
+		Mask:                expandAccessRights(expandStringSet(d.Get("rights"))),
+		ObjectType:          objectTypeGUID,
+		InheritedObjectType: inheritedObjectTypeGUID,
+		SID:                 trusteeSID,
+	}
+
+	deny := d.Get("type").(string) == "deny"
+	switch {
+	case entry.ObjectType != "" || entry.InheritedObjectType != "":
+		entry.Type = aceTypeAccessAllowedObject
+		if deny {
+			entry.Type = aceTypeAccessDeniedObject
+		}
+	case deny:
+		entry.Type = aceTypeAccessDenied
+	}
+
+	return entry
+}
+
+// stateACLEntry builds the ACE of the resource from its state
+func stateACLEntry(d *schema.ResourceData) ace {
+	return expandACLEntry(d, d.Get("trustee_sid").(string), d.Get("object_type_guid").(string), d.Get("inherited_object_type_guid").(string))
+}
+
+func resourceLDAPACLEntryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("target_dn").(string)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	trusteeSID, err := client.resolveTrusteeSID(d.Get("trustee").(string))
+	if err != nil {
+		return diag.Errorf("failed resolving trustee %s: %s", d.Get("trustee").(string), err)
+	}
+
+	objectTypeGUID := ""
+	if objectType := d.Get("object_type").(string); objectType != "" {
+		if objectTypeGUID, err = client.resolveObjectTypeGUID(objectType); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	inheritedObjectTypeGUID := ""
+	if inheritedObjectType := d.Get("inherited_object_type").(string); inheritedObjectType != "" {
+		if inheritedObjectTypeGUID, err = client.resolveObjectTypeGUID(inheritedObjectType); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	entry := expandACLEntry(d, trusteeSID, objectTypeGUID, inheritedObjectTypeGUID)
+
+	err = client.updateSecurityDescriptor(dn, sdFlagsDACL, func(sd *securityDescriptor) (bool, error) {
+		if sd.DACL == nil {
+			sd.DACL = &acl{}
+		}
+
+		// An existing ACE is not owned by the resource, destroying it would remove
+		// an ACE Terraform didn't create
+		if sd.DACL.hasACE(entry) {
+			return false, fmt.Errorf("the ACE %s already exists in the DACL of %q, import it with the ID \"%s|%s\"", entry.sddl(), dn, dn, entry.sddl())
+		}
+		sd.DACL.addACE(entry)
+
+		return true, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", dn, entry.sddl()))
+
+	values := map[string]interface{}{
+		"trustee_sid":                trusteeSID,
+		"object_type_guid":           objectTypeGUID,
+		"inherited_object_type_guid": inheritedObjectTypeGUID,
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPACLEntryRead(ctx, d, m)
+}
+
+func resourceLDAPACLEntryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("target_dn").(string)
+
+	sd, err := client.readSecurityDescriptor(dn, sdFlagsDACL)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// Only the ACE owned by the resource is compared, the other ACEs are left
+	// unmanaged. The resource is recreated if the ACE was removed.
+	if sd.DACL == nil || !sd.DACL.hasACE(stateACLEntry(d)) {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceLDAPACLEntryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("target_dn").(string)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.updateSecurityDescriptor(dn, sdFlagsDACL, func(sd *securityDescriptor) (bool, error) {
+		if sd.DACL == nil {
+			return false, nil
+		}
+
+		return sd.DACL.removeACE(stateACLEntry(d)), nil
+	})
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil
+	}
+
+	return diag.FromErr(err)
+}
+
+// resourceLDAPACLEntryImport imports an ACE by the DN of the LDAP object and the
+// SDDL string of the ACE, separated by `|`. The trustee and object types are
+// resolved to a DN and names when possible, and kept as SID and GUIDs otherwise.
+func resourceLDAPACLEntryImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*providerClient)
+
+	i := strings.LastIndex(d.Id(), "|")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid ID %q, expected <dn>|<sddl>", d.Id())
+	}
+	dn := d.Id()[:i]
+
+	entry, err := parseACESDDL(d.Id()[i+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Flags&aceFlagInherited != 0 {
+		return nil, fmt.Errorf("the ACE %s is inherited and can't be managed on %q", entry.sddl(), dn)
+	}
+
+	isObjectACE := entry.Type == aceTypeAccessAllowedObject || entry.Type == aceTypeAccessDeniedObject
+	if isObjectACE != (entry.ObjectType != "" || entry.InheritedObjectType != "") {
+		return nil, fmt.Errorf("the ACE %s must be an object ACE if and only if it has object types", entry.sddl())
+	}
+
+	inheritance := flattenACEInheritance(entry.Flags)
+	if inheritance == "" || entry.Flags&aceFlagObjectInherit != 0 {
+		return nil, fmt.Errorf("the inheritance flags of the ACE %s are not supported", entry.sddl())
+	}
+
+	rights := flattenAccessRights(entry.Mask)
+	if expandAccessRights(rights) != entry.Mask {
+		return nil, fmt.Errorf("the access mask of the ACE %s is not supported", entry.sddl())
+	}
+
+	aceType := "allow"
+	if entry.isDeny() {
+		aceType = "deny"
+	}
+
+	trustee := entry.SID
+	baseDN, err := client.baseDNOrDefault("")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.searchBySIDs(baseDN, []string{entry.SID}, []string{"distinguishedName"})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 1 {
+		trustee = entries[0].DN
+	}
+
+	guids := []string{}
+	for _, guid := range []string{entry.ObjectType, entry.InheritedObjectType} {
+		if guid != "" {
+			guids = append(guids, guid)
+		}
+	}
+	names, err := client.resolveObjectTypeNames(guids)
+	if err != nil {
+		return nil, err
+	}
+
+	objectTypeName := func(guid string) string {
+		if name, ok := names[guid]; ok {
+			return name
+		}
+		return guid
+	}
+
+	values := map[string]interface{}{
+		"target_dn":                  dn,
+		"trustee":                    trustee,
+		"type":                       aceType,
+		"rights":                     rights,
+		"object_type":                objectTypeName(entry.ObjectType),
+		"inherited_object_type":      objectTypeName(entry.InheritedObjectType),
+		"inheritance":                inheritance,
+		"trustee_sid":                entry.SID,
+		"object_type_guid":           entry.ObjectType,
+		"inherited_object_type_guid": entry.InheritedObjectType,
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return nil, err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", dn, entry.sddl()))
+
+	return []*schema.ResourceData{d}, nil
+}
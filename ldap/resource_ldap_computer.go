@@ -0,0 +1,417 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// computerStringAttributes maps the ldap_computer single valued arguments to their LDAP attribute
+var computerStringAttributes = map[string]string{
+	"description":                   "description",
+	"dns_host_name":                 "dNSHostName",
+	"managed_by":                    "managedBy",
+	"operating_system":              "operatingSystem",
+	"operating_system_version":      "operatingSystemVersion",
+	"operating_system_service_pack": "operatingSystemServicePack",
+}
+
+// computerReadAttributes are the attributes read for an LDAP computer
+var computerReadAttributes = []string{
+	"name",
+	"sAMAccountName",
+	"userAccountControl",
+	"objectSid",
+	"servicePrincipalName",
+	"description",
+	"dNSHostName",
+	"managedBy",
+	"operatingSystem",
+	"operatingSystemVersion",
+	"operatingSystemServicePack",
+}
+
+func resourceLDAPComputer() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_computer` is a resource for managing an LDAP computer account.",
+		CreateContext: resourceLDAPComputerCreate,
+		ReadContext:   resourceLDAPComputerRead,
+		UpdateContext: resourceLDAPComputerUpdate,
+		DeleteContext: resourceLDAPComputerDelete,
+		CustomizeDiff: customdiff.All(
+			resourceLDAPComputerCustomizeDiff,
+			customizeDiffCheckWriteAllowed("CN"),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPComputerImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP computer.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where LDAP computer will be created.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "LDAP computer name.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"sam_account_name": {
+				Description:      "The sAMAccountName (pre-Windows 2000 name) of the LDAP computer, the trailing `$` is added if missing, at most 15 characters long without it. Defaults to the upper case `name` followed by `$`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateComputerSAMAccountName,
+				DiffSuppressFunc: computerSAMAccountNameDiffSuppress,
+			},
+			"dns_host_name": {
+				Description: "The dNSHostName of the LDAP computer. Left unmanaged if not set, as it is usually set by the computer when joining the domain.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": {
+				Description: "Description attribute for the LDAP computer.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"operating_system": {
+				Description: "The operatingSystem of the LDAP computer. Left unmanaged if not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"operating_system_version": {
+				Description: "The operatingSystemVersion of the LDAP computer. Left unmanaged if not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"operating_system_service_pack": {
+				Description: "The operatingSystemServicePack of the LDAP computer. Left unmanaged if not set.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"managed_by": {
+				Description: "ManagedBy attribute",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+			},
+			"enabled": {
+				Description: "Whether the LDAP computer account is enabled. Default is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"service_principal_names": {
+				Description: "Service principal names of the LDAP computer. Left unmanaged if not set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"object_sid": {
+				Description: "The SID of the LDAP computer.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"delete_strategy": {
+				Description:  "How to handle the objects created by Windows under the LDAP computer (BitLocker recovery information, service connection points...) when destroying it: `fail` reports the objects blocking the deletion, `tree_delete` deletes the computer with all its content using the tree delete control. Default is `fail`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"fail", "tree_delete"}, false),
+			},
+		},
+	}
+}
+
+// computerSAMAccountName returns the sAMAccountName of a computer, with the trailing `$`
+func computerSAMAccountName(samAccountName string) string {
+	if strings.HasSuffix(samAccountName, "$") {
+		return samAccountName
+	}
+
+	return samAccountName + "$"
+}
+
+// resourceLDAPComputerCustomizeDiff rejects at plan time the creation of a computer
+// whose default sAMAccountName, built from its name, exceeds the NetBIOS name length
+func resourceLDAPComputerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.GetAttr("sam_account_name").IsNull() {
+		return nil
+	}
+
+	if name := d.Get("name").(string); len([]rune(name)) > computerNameMaxLength {
+		return fmt.Errorf("computer name %q is longer than %d characters, the NetBIOS name of a computer: set a shorter sam_account_name", name, computerNameMaxLength)
+	}
+
+	return nil
+}
+
+// computerSAMAccountNameDiffSuppress ignores the missing trailing `$` and case changes
+// of the computer sAMAccountName, which are both handled by Active Directory
+func computerSAMAccountNameDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	return new != "" && strings.EqualFold(computerSAMAccountName(old), computerSAMAccountName(new))
+}
+
+func resourceLDAPComputerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	name := d.Get("name").(string)
+	dn := fmt.Sprintf("CN=%s,%s", name, d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	samAccountName := strings.ToUpper(name) + "$"
+	if v := d.Get("sam_account_name").(string); v != "" {
+		samAccountName = computerSAMAccountName(v)
+	}
+
+	// Pre-staged computers are created like "Active Directory Users and Computers"
+	// does: a workstation trust account which doesn't require a password until
+	// the computer joins the domain
+	uac := uacWorkstationTrustAccount | uacPasswordNotRequired
+	if !d.Get("enabled").(bool) {
+		uac |= uacAccountDisable
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"computer"})
+	req.Attribute("sAMAccountName", []string{samAccountName})
+	req.Attribute("userAccountControl", []string{strconv.Itoa(uac)})
+
+	for attribute, ldapAttribute := range computerStringAttributes {
+		if value := d.Get(attribute).(string); value != "" {
+			req.Attribute(ldapAttribute, []string{value})
+		}
+	}
+
+	if spns := expandStringSet(d.Get("service_principal_names")); len(spns) > 0 {
+		req.Attribute("servicePrincipalName", spns)
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPComputerRead(ctx, d, m)
+}
+
+func resourceLDAPComputerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, computerReadAttributes)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// If Read is called from a datasource, return an error
+			if ctx.Value(CallerTypeKey) == DatasourceCaller {
+				return diag.Errorf("LDAP computer not found: %s", dn)
+			}
+
+			// If not a call from datasource, remove the resource from the state
+			// and cleanly return
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	ou, err := parentDN(dn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	uac, err := strconv.Atoi(entry.GetAttributeValue("userAccountControl"))
+	if err != nil {
+		return diag.Errorf("failed parsing userAccountControl of %s: %s", dn, err)
+	}
+
+	sid, err := decodeSID(entry.GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return diag.Errorf("failed decoding objectSid of %s: %s", dn, err)
+	}
+
+	values := map[string]interface{}{
+		"name":                    entry.GetAttributeValue("name"),
+		"ou":                      ou,
+		"sam_account_name":        entry.GetAttributeValue("sAMAccountName"),
+		"enabled":                 uac&uacAccountDisable == 0,
+		"service_principal_names": entry.GetAttributeValues("servicePrincipalName"),
+		"object_sid":              sid,
+	}
+	for attribute, ldapAttribute := range computerStringAttributes {
+		values[attribute] = entry.GetAttributeValue(ldapAttribute)
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Extra attributes are only exposed on the data source
+	if ctx.Value(CallerTypeKey) == DatasourceCaller {
+		if err := readExtraAttributes(ctx, client, dn, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPComputerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := updateStringAttributes(client, dn, d, computerStringAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("sam_account_name") {
+		samAccountName := computerSAMAccountName(d.Get("sam_account_name").(string))
+		if err := client.replaceAttribute(dn, "sAMAccountName", []string{samAccountName}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("service_principal_names") {
+		if err := client.replaceAttribute(dn, "servicePrincipalName", expandStringSet(d.Get("service_principal_names"))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("enabled") {
+		if err := client.setUserAccountControlFlag(dn, uacAccountDisable, !d.Get("enabled").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPComputerRead(ctx, d, m)
+}
+
+func resourceLDAPComputerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
+
+	if d.Get("delete_strategy").(string) == "tree_delete" {
+		if err := client.checkSubtreeDeleteAllowed(dn); err != nil {
+			return diag.FromErr(err)
+		}
+
+		err := client.Conn.Del(ldap.NewDelRequest(dn, []ldap.Control{ldap.NewControlSubtreeDelete()}))
+
+		return diag.FromErr(err)
+	}
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.Conn.Del(ldap.NewDelRequest(dn, nil))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+		// Report the objects blocking the deletion, like BitLocker recovery information
+		children, searchErr := client.searchChildrenDNs(dn)
+		if searchErr != nil {
+			return diag.FromErr(err)
+		}
+
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("LDAP computer %s is not empty", dn),
+				Detail: fmt.Sprintf(
+					"The following objects must be removed before destroying the computer, or delete_strategy must be set to \"tree_delete\":\n%s",
+					strings.Join(children, "\n"),
+				),
+			},
+		}
+	}
+
+	return diag.FromErr(err)
+}
+
+// resourceLDAPComputerImport imports an LDAP computer by DN, or by name or
+// sAMAccountName searched in the whole default naming context
+func resourceLDAPComputerImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*providerClient)
+
+	if _, err := ldap.ParseDN(d.Id()); err == nil && strings.Contains(d.Id(), "=") {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	baseDN, err := client.baseDNOrDefault("")
+	if err != nil {
+		return nil, err
+	}
+
+	dn, err := client.searchComputerByName(d.Id(), baseDN, ldap.ScopeWholeSubtree)
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(dn)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// searchComputerByName returns the DN of the computer with the given name or
+// sAMAccountName, with or without the trailing `$`
+func (c *providerClient) searchComputerByName(name, baseDN string, scope int) (string, error) {
+	name = strings.TrimSuffix(name, "$")
+	filter := fmt.Sprintf("(&(objectClass=computer)(|(cn=%s)(sAMAccountName=%s$)))", ldap.EscapeFilter(name), ldap.EscapeFilter(name))
+
+	entries, err := c.search(baseDN, scope, filter, []string{"distinguishedName"})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(entries) {
+	case 0:
+		return "", ldap.NewError(ldap.LDAPResultNoSuchObject, fmt.Errorf("LDAP computer %q not found in %q", name, baseDN))
+	case 1:
+		return entries[0].DN, nil
+	}
+
+	dns := []string{}
+	for _, entry := range entries {
+		dns = append(dns, entry.DN)
+	}
+
+	return "", fmt.Errorf("several LDAP computers named %q found in %q: %s", name, baseDN, strings.Join(dns, ", "))
+}
@@ -0,0 +1,354 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// contactReservedAttributes are the LDAP attributes managed by the
+// dedicated arguments of ldap_contact, which can't be set in attributes
+var contactReservedAttributes = []string{
+	"cn",
+	"name",
+	"distinguishedName",
+	"objectClass",
+	"displayName",
+	"mail",
+	"targetAddress",
+	"proxyAddresses",
+	"company",
+	"description",
+	"nTSecurityDescriptor",
+}
+
+// contactStringAttributes maps the ldap_contact single valued arguments to their LDAP attribute
+var contactStringAttributes = map[string]string{
+	"display_name":   "displayName",
+	"mail":           "mail",
+	"target_address": "targetAddress",
+	"company":        "company",
+	"description":    "description",
+}
+
+// contactReadAttributes are the attributes read for an LDAP contact
+var contactReadAttributes = []string{
+	"name",
+	"displayName",
+	"mail",
+	"targetAddress",
+	"proxyAddresses",
+	"company",
+	"description",
+}
+
+func resourceLDAPContact() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_contact` is a resource for managing an LDAP contact.",
+		CreateContext: resourceLDAPContactCreate,
+		ReadContext:   resourceLDAPContactRead,
+		UpdateContext: resourceLDAPContactUpdate,
+		DeleteContext: resourceLDAPContactDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffCheckWriteAllowed("CN"),
+			customizeDiffCheckParentsWriteAllowed,
+			customizeDiffCheckAttributes(contactReservedAttributes),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP contact.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where LDAP contact will be created.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "LDAP contact name.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"display_name": {
+				Description:      "The displayName of the contact",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("displayName"),
+			},
+			"mail": {
+				Description:      "The mail address of the contact",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("mail"),
+			},
+			"target_address": {
+				Description:      "The Exchange targetAddress of the contact (e.g. `SMTP:partner@external.tld`), mails sent to the contact are forwarded to it",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("targetAddress"),
+			},
+			"proxy_addresses": {
+				Description:      "The Exchange proxyAddresses of the contact. Left unmanaged if not set.",
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("proxyAddresses"),
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"company": {
+				Description:      "The company of the contact",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("company"),
+			},
+			"description": {
+				Description:      "Description attribute for the LDAP contact.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("description"),
+			},
+			"create_parents": {
+				Description: "Create the missing OUs in the path of `ou` when creating the LDAP contact. Only the OUs created this way are deleted when destroying the contact, and only if they are empty. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"created_parents": {
+				Description: "DNs of the parent OUs created because of `create_parents`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attributes":        attributesResourceSchema("contact"),
+			"ignore_attributes": ignoreAttributesSchema("contact"),
+			"adopt_existing": {
+				Description: "Adopt the LDAP contact if it already exists when creating it, instead of failing. Its attributes are then reconciled with the configuration. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"deletion_protection": {
+				Description: "Prevent the LDAP contact from being destroyed. It must be set to `false` and applied before the contact can be destroyed. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"protect_from_accidental_deletion": {
+				Description: "Set the Active Directory \"Protect object from accidental deletion\" deny ACE on the LDAP contact, protecting it from deletion outside of Terraform too. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceLDAPContactCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	adopt, err := adoptExistingEntry(client, d, dn, "contact")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if adopt {
+		if err := resourceLDAPContactReconcile(client, dn, d); err != nil {
+			return diag.FromErr(err)
+		}
+		diags = append(diags, adoptionWarning("contact", dn))
+	} else {
+		if err := createParents(client, d); err != nil {
+			return diag.FromErr(err)
+		}
+
+		req := ldap.NewAddRequest(dn, nil)
+		req.Attribute("objectClass", []string{"contact"})
+
+		for attribute, ldapAttribute := range contactStringAttributes {
+			if value := d.Get(attribute).(string); value != "" {
+				req.Attribute(ldapAttribute, []string{value})
+			}
+		}
+
+		if proxyAddresses := expandStringSet(d.Get("proxy_addresses")); len(proxyAddresses) > 0 {
+			req.Attribute("proxyAddresses", proxyAddresses)
+		}
+
+		if err := client.Conn.Add(req); err != nil {
+			return diag.FromErr(cleanupCreatedParents(client, d, err))
+		}
+	}
+
+	if err := client.updateAttributes(dn, nil, expandAttributes(d.Get("attributes"))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	if d.Get("protect_from_accidental_deletion").(bool) {
+		if err := client.setAccidentalDeletionProtection(dn, true); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return append(diags, resourceLDAPContactRead(ctx, d, m)...)
+}
+
+// resourceLDAPContactReconcile updates the attributes of an adopted LDAP contact
+// which differ from the configuration
+func resourceLDAPContactReconcile(client *providerClient, dn string, d *schema.ResourceData) error {
+	entry, err := client.readEntry(dn, contactReadAttributes)
+	if err != nil {
+		return err
+	}
+
+	if err := reconcileStringAttributes(client, entry, d, contactStringAttributes); err != nil {
+		return err
+	}
+
+	if value, ok := d.GetOk("proxy_addresses"); ok && !isIgnoredAttribute(d, "proxyAddresses") {
+		current := schema.NewSet(schema.HashString, []interface{}{})
+		for _, proxyAddress := range entry.GetAttributeValues("proxyAddresses") {
+			current.Add(proxyAddress)
+		}
+		if !current.Equal(value.(*schema.Set)) {
+			if err := client.replaceAttribute(dn, "proxyAddresses", expandStringSet(value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPContactRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, contactReadAttributes)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	ou, err := parentDN(dn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name":            entry.GetAttributeValue("name"),
+		"ou":              ou,
+		"proxy_addresses": entry.GetAttributeValues("proxyAddresses"),
+	}
+	for attribute, ldapAttribute := range contactStringAttributes {
+		values[attribute] = entry.GetAttributeValue(ldapAttribute)
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := readExtraAttributes(ctx, client, dn, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	protected, err := client.readAccidentalDeletionProtection(dn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("protect_from_accidental_deletion", protected)
+
+	return diag.FromErr(err)
+}
+
+func resourceLDAPContactUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := updateStringAttributes(client, dn, d, contactStringAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("proxy_addresses") {
+		if err := client.replaceAttribute(dn, "proxyAddresses", expandStringSet(d.Get("proxy_addresses"))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("attributes") {
+		old, new := d.GetChange("attributes")
+		if err := client.updateAttributes(dn, expandAttributes(old), expandAttributes(new)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("protect_from_accidental_deletion") {
+		if err := client.setAccidentalDeletionProtection(dn, d.Get("protect_from_accidental_deletion").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPContactRead(ctx, d, m)
+}
+
+func resourceLDAPContactDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("LDAP contact %s has deletion_protection enabled, set it to false and apply before destroying it", dn)
+	}
+
+	// The Active Directory protection managed by Terraform is removed to allow the deletion
+	err := client.deleteProtectedObject(dn, d.Get("protect_from_accidental_deletion").(bool), func() error {
+		return client.Conn.Del(ldap.NewDelRequest(dn, nil))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteCreatedParents(client, d)
+
+	return diag.FromErr(err)
+}
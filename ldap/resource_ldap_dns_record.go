@@ -0,0 +1,336 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLDAPDNSRecord() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_dns_record` is a resource for managing the records of a given name and type in an Active Directory integrated DNS zone.",
+		CreateContext: resourceLDAPDNSRecordCreate,
+		ReadContext:   resourceLDAPDNSRecordRead,
+		UpdateContext: resourceLDAPDNSRecordUpdate,
+		DeleteContext: resourceLDAPDNSRecordDelete,
+		CustomizeDiff: resourceLDAPDNSRecordCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceLDAPDNSRecordImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the dnsNode and the record type, separated by `|`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"zone": {
+				Description: "The DN of the DNS zone.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description: "The name of the records relative to the zone, `@` for the zone apex.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"type": {
+				Description:  "The record type (`A`, `AAAA`, `CNAME`, `PTR`, `SRV` or `TXT`).",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(dnsRecordTypeNames(), false),
+			},
+			"records": {
+				Description: "The record values: an IP address for `A` and `AAAA`, a host name without trailing dot for `CNAME` and `PTR`, `<priority> <weight> <port> <target>` for `SRV` and the text for `TXT`.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ttl": {
+				Description:  "The TTL of the records in seconds. Default is `3600`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validation.IntBetween(0, 2147483647),
+			},
+		},
+	}
+}
+
+// resourceLDAPDNSRecordCustomizeDiff rejects at plan time the record values which
+// would be read back differently, and changes on a dnsNode whose DN is forbidden
+// by the provider configuration
+func resourceLDAPDNSRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("type") && d.NewValueKnown("records") {
+		recordType := dnsRecordTypes[d.Get("type").(string)]
+		records := expandStringSet(d.Get("records"))
+
+		if recordType == dnsTypeCNAME && len(records) > 1 {
+			return fmt.Errorf("a CNAME record can only have one value, got %d", len(records))
+		}
+
+		for _, value := range records {
+			data, err := encodeDNSRecordData(recordType, value)
+			if err != nil {
+				return err
+			}
+			decoded, err := decodeDNSRecordData(recordType, data)
+			if err != nil {
+				return err
+			}
+			if decoded != value {
+				return fmt.Errorf("%s record %q must be written %q", d.Get("type").(string), value, decoded)
+			}
+		}
+	}
+
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Nothing will be written if the plan is empty
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("zone") || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	return client.checkWriteAllowed(dnsNodeDN(d.Get("name").(string), d.Get("zone").(string)))
+}
+
+// parseDNSRecordID parses a ldap_dns_record id to the dnsNode DN and the record type name
+func parseDNSRecordID(id string) (string, string, error) {
+	i := strings.LastIndex(id, "|")
+	if i < 0 {
+		return "", "", fmt.Errorf("invalid DNS record id %q, expected `<dnsNode DN>|<type>`", id)
+	}
+
+	recordType := strings.ToUpper(id[i+1:])
+	if _, ok := dnsRecordTypes[recordType]; !ok {
+		return "", "", fmt.Errorf("invalid DNS record id %q, unsupported type %q", id, id[i+1:])
+	}
+
+	return id[:i], recordType, nil
+}
+
+// expandDNSRecords builds the DNS records of the resource, stamped with the zone serial
+func expandDNSRecords(d *schema.ResourceData, serial uint32) ([]dnsRecord, error) {
+	recordType := dnsRecordTypes[d.Get("type").(string)]
+
+	records := []dnsRecord{}
+	for _, value := range expandStringSet(d.Get("records")) {
+		data, err := encodeDNSRecordData(recordType, value)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, dnsRecord{
+			Type:   recordType,
+			Rank:   dnsRankZone,
+			Serial: serial,
+			TTL:    uint32(d.Get("ttl").(int)),
+			Data:   data,
+		})
+	}
+
+	return records, nil
+}
+
+// resourceLDAPDNSRecordWrite writes the records of the resource to its dnsNode
+func resourceLDAPDNSRecordWrite(client *providerClient, d *schema.ResourceData, dn string) error {
+	zone := d.Get("zone").(string)
+
+	serial, err := client.nextDNSSerial(zone)
+	if err != nil {
+		return err
+	}
+
+	records, err := expandDNSRecords(d, serial)
+	if err != nil {
+		return err
+	}
+
+	return client.updateDNSRecords(dn, dnsRecordTypes[d.Get("type").(string)], records, serial)
+}
+
+func resourceLDAPDNSRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := dnsNodeDN(d.Get("name").(string), d.Get("zone").(string))
+	typeName := d.Get("type").(string)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The records of the type would be replaced, refuse to take over records
+	// not created by this resource
+	node, err := client.readDNSNode(dn)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return diag.FromErr(err)
+	}
+	if err == nil && !node.Tombstoned {
+		for _, record := range node.Records {
+			if record.Type == dnsRecordTypes[typeName] {
+				return diag.Errorf("%s records already exist on %q, import them with the ID \"%s|%s\"", typeName, dn, dn, typeName)
+			}
+		}
+	}
+
+	if err := resourceLDAPDNSRecordWrite(client, d, dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", dn, typeName))
+
+	return resourceLDAPDNSRecordRead(ctx, d, m)
+}
+
+func resourceLDAPDNSRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn, typeName, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	node, err := client.readDNSNode(dn)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Node doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// A tombstoned node was deleted by the DNS server and has no records left
+	if node.Tombstoned {
+		d.SetId("")
+		return nil
+	}
+
+	recordType := dnsRecordTypes[typeName]
+	records := []string{}
+	ttl := 0
+	for _, record := range node.Records {
+		if record.Type != recordType {
+			continue
+		}
+
+		value, err := decodeDNSRecordData(recordType, record.Data)
+		if err != nil {
+			return diag.Errorf("failed decoding %s record of %s: %s", typeName, dn, err)
+		}
+		records = append(records, value)
+		ttl = int(record.TTL)
+	}
+
+	if len(records) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	values := map[string]interface{}{
+		"type":    typeName,
+		"records": records,
+		"ttl":     ttl,
+	}
+
+	// The zone and name are only set from the DN when importing, to keep the configured DN spelling
+	if d.Get("zone").(string) == "" {
+		parsedDN, err := ldap.ParseDN(dn)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(parsedDN.RDNs) < 2 || len(parsedDN.RDNs[0].Attributes) != 1 {
+			return diag.Errorf("invalid dnsNode DN %q", dn)
+		}
+		values["zone"] = joinRDNs(parsedDN.RDNs[1:])
+		values["name"] = parsedDN.RDNs[0].Attributes[0].Value
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPDNSRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn, _, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("records", "ttl") {
+		if err := resourceLDAPDNSRecordWrite(client, d, dn); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPDNSRecordRead(ctx, d, m)
+}
+
+func resourceLDAPDNSRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn, typeName, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	serial, err := client.nextDNSSerial(d.Get("zone").(string))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		// The zone was deleted with its records
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.updateDNSRecords(dn, dnsRecordTypes[typeName], nil, serial)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil
+	}
+
+	return diag.FromErr(err)
+}
+
+// resourceLDAPDNSRecordImport validates the `<dnsNode DN>|<type>` id of the imported records
+func resourceLDAPDNSRecordImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	dn, typeName, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", dn, typeName))
+
+	return []*schema.ResourceData{d}, nil
+}
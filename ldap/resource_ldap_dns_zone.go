@@ -0,0 +1,296 @@
+package ldap
+
+import (
+	"context"
+	"encoding/binary"
+	"regexp"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// SOA record values of the zones created by ldap_dns_zone, the DNS server defaults
+const (
+	dnsZoneRefresh    = 900
+	dnsZoneRetry      = 600
+	dnsZoneExpire     = 86400
+	dnsZoneMinimumTTL = 3600
+	dnsZoneTTL        = 3600
+)
+
+func resourceLDAPDNSZone() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_dns_zone` is a resource for managing an Active Directory integrated DNS zone.",
+		CreateContext: resourceLDAPDNSZoneCreate,
+		ReadContext:   resourceLDAPDNSZoneRead,
+		UpdateContext: resourceLDAPDNSZoneUpdate,
+		DeleteContext: resourceLDAPDNSZoneDelete,
+		CustomizeDiff: resourceLDAPDNSZoneCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the DNS zone.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description:  "The DNS zone name, without trailing dot (e.g. `domain.tld` or `1.168.192.in-addr.arpa`).",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile(`\.$`), "must not end with a dot"),
+			},
+			"partition": {
+				Description:  "The partition storing the zone: `domain` (DomainDnsZones, replicated to the DNS servers of the domain), `forest` (ForestDnsZones, replicated to the DNS servers of the forest) or `legacy` (the System container of the domain partition, replicated to all the domain controllers). Default is `domain`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "domain",
+				ValidateFunc: validation.StringInSlice([]string{"domain", "forest", "legacy"}, false),
+			},
+			"dynamic_update": {
+				Description:  "The dynamic updates allowed on the zone (`none`, `secure` or `nonsecure_and_secure`). Default is `secure`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "secure",
+				ValidateFunc: validation.StringInSlice(dnsAllowUpdateNames(), false),
+			},
+			"primary_server": {
+				Description:  "The primary server of the SOA record, without trailing dot, also used as NS record of the zone. Defaults to the DNS host name of the LDAP server.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringDoesNotMatch(regexp.MustCompile(`\.$`), "must not end with a dot"),
+			},
+			"serial": {
+				Description: "The serial of the SOA record of the zone.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"deletion_protection": {
+				Description: "Prevent the DNS zone from being destroyed. It must be set to `false` and applied before the zone can be destroyed. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+// resourceLDAPDNSZoneCustomizeDiff rejects at plan time changes on a
+// DNS zone whose DN is forbidden by the provider configuration
+func resourceLDAPDNSZoneCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Nothing will be written if the plan is empty
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	if d.Id() != "" && !d.HasChange("name") && !d.HasChange("partition") {
+		return client.checkWriteAllowed(d.Id())
+	}
+
+	if !d.NewValueKnown("name") || !d.NewValueKnown("partition") {
+		return nil
+	}
+
+	container, err := client.dnsZoneContainer(d.Get("partition").(string))
+	if err != nil {
+		return err
+	}
+
+	return client.checkWriteAllowed(dnsNodeDN(d.Get("name").(string), container))
+}
+
+func resourceLDAPDNSZoneCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	name := d.Get("name").(string)
+
+	container, err := client.dnsZoneContainer(d.Get("partition").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dn := dnsNodeDN(name, container)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	primaryServer := d.Get("primary_server").(string)
+	if primaryServer == "" {
+		rootDSE, err := client.readRootDSE([]string{"dnsHostName"})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if primaryServer = rootDSE.GetAttributeValue("dnsHostName"); primaryServer == "" {
+			return diag.Errorf("the LDAP server root DSE has no dnsHostName, set primary_server")
+		}
+	}
+
+	zoneType := make([]byte, 4)
+	binary.LittleEndian.PutUint32(zoneType, dnsZoneTypePrimary)
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"dnsZone"})
+	req.Attribute("dNSProperty", []string{
+		string(encodeDNSProperty(dnsPropertyZoneType, zoneType)),
+		string(encodeDNSProperty(dnsPropertyAllowUpdate, []byte{dnsAllowUpdates[d.Get("dynamic_update").(string)]})),
+	})
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	soa, err := dnsSOA{
+		Serial:            1,
+		Refresh:           dnsZoneRefresh,
+		Retry:             dnsZoneRetry,
+		Expire:            dnsZoneExpire,
+		MinimumTTL:        dnsZoneMinimumTTL,
+		PrimaryServer:     primaryServer,
+		ResponsiblePerson: "hostmaster." + name,
+	}.encode()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ns, err := encodeDNSName(primaryServer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records := []dnsRecord{
+		{Type: dnsTypeSOA, Rank: dnsRankZone, Serial: 1, TTL: dnsZoneTTL, Data: soa},
+		{Type: dnsTypeNS, Rank: dnsRankZone, Serial: 1, TTL: dnsZoneTTL, Data: ns},
+	}
+
+	apex := ldap.NewAddRequest(dnsNodeDN(dnsApexName, dn), nil)
+	apex.Attribute("objectClass", []string{"dnsNode"})
+	apex.Attribute("dnsRecord", []string{string(records[0].encode()), string(records[1].encode())})
+
+	if err := client.Conn.Add(apex); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceLDAPDNSZoneRead(ctx, d, m)
+}
+
+func resourceLDAPDNSZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"name", "dNSProperty"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Zone doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	dynamicUpdate := "none"
+	for _, raw := range entry.GetRawAttributeValues("dNSProperty") {
+		id, data, err := decodeDNSProperty(raw)
+		if err != nil {
+			return diag.Errorf("failed decoding dNSProperty of %s: %s", dn, err)
+		}
+		if id != dnsPropertyAllowUpdate || len(data) == 0 {
+			continue
+		}
+		for name, value := range dnsAllowUpdates {
+			if data[0] == value {
+				dynamicUpdate = name
+			}
+		}
+	}
+
+	// A zone whose apex was removed outside of Terraform has no SOA record
+	soa, _, err := client.readDNSSOA(dn)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return diag.FromErr(err)
+	}
+	if soa == nil {
+		soa = &dnsSOA{}
+	}
+
+	values := map[string]interface{}{
+		"name":           entry.GetAttributeValue("name"),
+		"partition":      dnsZonePartition(dn),
+		"dynamic_update": dynamicUpdate,
+		"primary_server": soa.PrimaryServer,
+		"serial":         int(soa.Serial),
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPDNSZoneUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("dynamic_update") {
+		entry, err := client.readEntry(dn, []string{"dNSProperty"})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Only the allow update property is replaced, the other ones are managed by the DNS server
+		req := ldap.NewModifyRequest(dn, nil)
+		for _, raw := range entry.GetRawAttributeValues("dNSProperty") {
+			if id, _, err := decodeDNSProperty(raw); err == nil && id == dnsPropertyAllowUpdate {
+				req.Delete("dNSProperty", []string{string(raw)})
+			}
+		}
+		req.Add("dNSProperty", []string{string(encodeDNSProperty(dnsPropertyAllowUpdate, []byte{dnsAllowUpdates[d.Get("dynamic_update").(string)]}))})
+
+		if err := client.Conn.Modify(req); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPDNSZoneRead(ctx, d, m)
+}
+
+func resourceLDAPDNSZoneDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkSubtreeDeleteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("DNS zone %s has deletion_protection enabled, set it to false and apply before destroying it", dn)
+	}
+
+	// The zone is deleted with all its dnsNode objects
+	err := client.Conn.Del(ldap.NewDelRequest(dn, []ldap.Control{ldap.NewControlSubtreeDelete()}))
+
+	return diag.FromErr(err)
+}
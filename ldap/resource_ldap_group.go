@@ -5,12 +5,28 @@ import (
 	"fmt"
 	"regexp"
 
-	"github.com/Ouest-France/goldap"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// groupReservedAttributes are the LDAP attributes managed by the
+// dedicated arguments of ldap_group, which can't be set in attributes
+var groupReservedAttributes = []string{
+	"cn",
+	"name",
+	"distinguishedName",
+	"objectClass",
+	"sAMAccountName",
+	"description",
+	"member",
+	"groupType",
+	"managedBy",
+	"displayName",
+	"nTSecurityDescriptor",
+}
+
 func resourceLDAPGroup() *schema.Resource {
 	return &schema.Resource{
 		Description:   "`ldap_group` is a resource for managing an LDAP group.",
@@ -18,6 +34,11 @@ func resourceLDAPGroup() *schema.Resource {
 		ReadContext:   resourceLDAPGroupRead,
 		UpdateContext: resourceLDAPGroupUpdate,
 		DeleteContext: resourceLDAPGroupDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffCheckWriteAllowed("CN"),
+			customizeDiffCheckParentsWriteAllowed,
+			customizeDiffCheckAttributes(groupReservedAttributes),
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -41,15 +62,17 @@ func resourceLDAPGroup() *schema.Resource {
 				ForceNew:    true,
 			},
 			"description": {
-				Description: "Description attribute for the LDAP group.",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "Description attribute for the LDAP group.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("description"),
 			},
 			"members": {
-				Description: " LDAP group members DN",
-				Type:        schema.TypeSet,
-				Optional:    true,
-				Computed:    true,
+				Description:      " LDAP group members DN",
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("member"),
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -70,51 +93,191 @@ func resourceLDAPGroup() *schema.Resource {
 				ForceNew:    true,
 			},
 			"managed_by": {
-				Description: "ManagedBy attribute",
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "",
+				Description:      "ManagedBy attribute",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("managedBy"),
 			},
 			"display_name": {
-				Description: "The displayName of the group",
-				Type:        schema.TypeString,
+				Description:      "The displayName of the group",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("displayName"),
+			},
+			"sam_account_name": {
+				Description:      "The sAMAccountName (pre-Windows 2000 name) of the LDAP group. Defaults to `name`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateSAMAccountName,
+			},
+			"create_parents": {
+				Description: "Create the missing OUs in the path of `ou` when creating the LDAP group. Only the OUs created this way are deleted when destroying the group, and only if they are empty. Default is `false`.",
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     "",
+				Default:     false,
+			},
+			"created_parents": {
+				Description: "DNs of the parent OUs created because of `create_parents`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attributes":        attributesResourceSchema("group"),
+			"ignore_attributes": ignoreAttributesSchema("group"),
+			"adopt_existing": {
+				Description: "Adopt the LDAP group if it already exists when creating it, instead of failing. Its attributes are then reconciled with the configuration. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"deletion_protection": {
+				Description: "Prevent the LDAP group from being destroyed. It must be set to `false` and applied before the group can be destroyed. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"protect_from_accidental_deletion": {
+				Description: "Set the Active Directory \"Protect object from accidental deletion\" deny ACE on the LDAP group, protecting it from deletion outside of Terraform too. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
 			},
 		},
 	}
 }
 
 func resourceLDAPGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
 	members := []string{}
 	memberSet := d.Get("members").(*schema.Set)
 	for _, member := range memberSet.List() {
 		members = append(members, member.(string))
 	}
 
-	err := client.CreateGroup(dn, d.Get("name").(string), d.Get("description").(string), d.Get("group_type").(string), d.Get("managed_by").(string), d.Get("display_name").(string), members)
+	var diags diag.Diagnostics
+
+	adopt, err := adoptExistingEntry(client, d, dn, "group")
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	groupType := d.Get("group_type").(string)
-	if groupType != "" {
-		err := client.UpdateGroupType(dn, groupType)
-		if err != nil {
+
+	if adopt {
+		if err := resourceLDAPGroupReconcile(client, dn, d, members); err != nil {
 			return diag.FromErr(err)
 		}
+		diags = append(diags, adoptionWarning("group", dn))
+	} else {
+		if err := createParents(client, d); err != nil {
+			return diag.FromErr(err)
+		}
+
+		err := client.CreateGroup(dn, d.Get("name").(string), d.Get("description").(string), d.Get("group_type").(string), d.Get("managed_by").(string), d.Get("display_name").(string), members)
+		if err != nil {
+			return diag.FromErr(cleanupCreatedParents(client, d, err))
+		}
+		groupType := d.Get("group_type").(string)
+		if groupType != "" {
+			err := client.UpdateGroupType(dn, groupType)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		// The group is created with its name as sAMAccountName
+		samAccountName := d.Get("sam_account_name").(string)
+		if samAccountName != "" && samAccountName != d.Get("name").(string) {
+			if err := client.replaceAttribute(dn, "sAMAccountName", []string{samAccountName}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err := client.updateAttributes(dn, nil, expandAttributes(d.Get("attributes"))); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(dn)
 
-	return resourceLDAPGroupRead(ctx, d, m)
+	if d.Get("protect_from_accidental_deletion").(bool) {
+		if err := client.setAccidentalDeletionProtection(dn, true); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return append(diags, resourceLDAPGroupRead(ctx, d, m)...)
+}
+
+// resourceLDAPGroupReconcile updates the attributes of an adopted LDAP group
+// which differ from the configuration
+func resourceLDAPGroupReconcile(client *providerClient, dn string, d *schema.ResourceData, members []string) error {
+	attributes, err := client.ReadGroup(dn, 1500)
+	if err != nil {
+		return err
+	}
+
+	currentMembers := schema.NewSet(schema.HashString, []interface{}{})
+	for _, member := range attributes["member"] {
+		currentMembers.Add(member)
+	}
+	if !isIgnoredAttribute(d, "member") && !currentMembers.Equal(d.Get("members").(*schema.Set)) {
+		if err := client.UpdateGroupMembers(dn, members); err != nil {
+			return err
+		}
+	}
+
+	if description := d.Get("description").(string); !isIgnoredAttribute(d, "description") && description != firstAttributeValue(attributes, "description") {
+		if err := client.UpdateGroupDescription(dn, description); err != nil {
+			return err
+		}
+	}
+
+	if groupType := d.Get("group_type").(string); groupType != "" && groupType != firstAttributeValue(attributes, "groupType") {
+		if err := client.UpdateGroupType(dn, groupType); err != nil {
+			return err
+		}
+	}
+
+	if managedBy := d.Get("managed_by").(string); !isIgnoredAttribute(d, "managedBy") && managedBy != firstAttributeValue(attributes, "managedBy") {
+		if err := client.UpdateGroupManagedBy(dn, managedBy); err != nil {
+			return err
+		}
+	}
+
+	if displayName := d.Get("display_name").(string); !isIgnoredAttribute(d, "displayName") && displayName != firstAttributeValue(attributes, "displayName") {
+		if err := client.UpdateGroupDisplayName(dn, displayName); err != nil {
+			return err
+		}
+	}
+
+	if samAccountName := d.Get("sam_account_name").(string); samAccountName != "" {
+		entry, err := client.readEntry(dn, []string{"sAMAccountName"})
+		if err != nil {
+			return err
+		}
+		if samAccountName != entry.GetAttributeValue("sAMAccountName") {
+			if err := client.replaceAttribute(dn, "sAMAccountName", []string{samAccountName}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func resourceLDAPGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	dn := d.Id()
 
@@ -185,6 +348,29 @@ func resourceLDAPGroupRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.FromErr(err)
 	}
 
+	entry, err := client.readEntry(dn, []string{"sAMAccountName"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("sam_account_name", entry.GetAttributeValue("sAMAccountName")); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := readExtraAttributes(ctx, client, dn, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Accidental deletion protection is only exposed on the resource
+	if ctx.Value(CallerTypeKey) != DatasourceCaller {
+		protected, err := client.readAccidentalDeletionProtection(dn)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("protect_from_accidental_deletion", protected); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	members := []string{}
 	members_names := []string{}
 	for name, values := range attributes {
@@ -210,9 +396,13 @@ func resourceLDAPGroupRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceLDAPGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if d.HasChange("members") {
 		members := []string{}
 		memberSet := d.Get("members").(*schema.Set)
@@ -249,15 +439,50 @@ func resourceLDAPGroupUpdate(ctx context.Context, d *schema.ResourceData, m inte
 		}
 	}
 
+	if d.HasChange("sam_account_name") {
+		if err := client.replaceAttribute(dn, "sAMAccountName", []string{d.Get("sam_account_name").(string)}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("attributes") {
+		old, new := d.GetChange("attributes")
+		if err := client.updateAttributes(dn, expandAttributes(old), expandAttributes(new)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("protect_from_accidental_deletion") {
+		if err := client.setAccidentalDeletionProtection(dn, d.Get("protect_from_accidental_deletion").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceLDAPGroupRead(ctx, d, m)
 }
 
 func resourceLDAPGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
-	err := client.DeleteGroup(dn)
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("LDAP group %s has deletion_protection enabled, set it to false and apply before destroying it", dn)
+	}
+
+	// The Active Directory protection managed by Terraform is removed to allow the deletion
+	err := client.deleteProtectedObject(dn, d.Get("protect_from_accidental_deletion").(bool), func() error {
+		return client.DeleteGroup(dn)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteCreatedParents(client, d)
 
 	return diag.FromErr(err)
 }
@@ -0,0 +1,218 @@
+package ldap
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPKerberosDelegation() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_kerberos_delegation` is a resource for managing the Kerberos constrained and resource-based constrained delegation settings of an existing LDAP account.",
+		CreateContext: resourceLDAPKerberosDelegationCreate,
+		ReadContext:   resourceLDAPKerberosDelegationRead,
+		UpdateContext: resourceLDAPKerberosDelegationUpdate,
+		DeleteContext: resourceLDAPKerberosDelegationDelete,
+		CustomizeDiff: resourceLDAPKerberosDelegationCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP account.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"account_dn": {
+				Description: "The DN of the LDAP user, computer or service account.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"allowed_to_delegate_to": {
+				Description: "Service principal names the account is allowed to delegate to (constrained delegation, msDS-AllowedToDelegateTo).",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"protocol_transition": {
+				Description: "Allow constrained delegation with any authentication protocol (protocol transition), through the TRUSTED_TO_AUTH_FOR_DELEGATION flag of userAccountControl. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"principals_allowed_to_delegate": {
+				Description: "DNs of the accounts allowed to delegate to this account (resource-based constrained delegation, msDS-AllowedToActOnBehalfOfOtherIdentity).",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceLDAPKerberosDelegationCustomizeDiff rejects at plan time changes on an
+// account whose DN is forbidden by the provider configuration
+func resourceLDAPKerberosDelegationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Nothing will be written if the plan is empty
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("account_dn") {
+		return nil
+	}
+
+	return client.checkWriteAllowed(d.Get("account_dn").(string))
+}
+
+func resourceLDAPKerberosDelegationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("account_dn").(string)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.replaceAttribute(dn, "msDS-AllowedToDelegateTo", expandStringSet(d.Get("allowed_to_delegate_to"))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.setUserAccountControlFlag(dn, uacTrustedToAuthForDelegation, d.Get("protocol_transition").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.updateAllowedToActOnBehalfOf(dn, expandStringSet(d.Get("principals_allowed_to_delegate"))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPKerberosDelegationRead(ctx, d, m)
+}
+
+func resourceLDAPKerberosDelegationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"msDS-AllowedToDelegateTo", "msDS-AllowedToActOnBehalfOfOtherIdentity", "userAccountControl"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Account doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	uac, err := strconv.Atoi(entry.GetAttributeValue("userAccountControl"))
+	if err != nil {
+		return diag.Errorf("failed parsing userAccountControl of %s: %s", dn, err)
+	}
+
+	principals, err := client.decodePrincipals(entry.GetRawAttributeValue("msDS-AllowedToActOnBehalfOfOtherIdentity"))
+	if err != nil {
+		return diag.Errorf("failed decoding msDS-AllowedToActOnBehalfOfOtherIdentity of %s: %s", dn, err)
+	}
+
+	values := map[string]interface{}{
+		"account_dn":                     dn,
+		"allowed_to_delegate_to":         entry.GetAttributeValues("msDS-AllowedToDelegateTo"),
+		"protocol_transition":            uac&uacTrustedToAuthForDelegation != 0,
+		"principals_allowed_to_delegate": keepConfiguredDNs(principals, d.Get("principals_allowed_to_delegate")),
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPKerberosDelegationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("allowed_to_delegate_to") {
+		if err := client.replaceAttribute(dn, "msDS-AllowedToDelegateTo", expandStringSet(d.Get("allowed_to_delegate_to"))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("protocol_transition") {
+		if err := client.setUserAccountControlFlag(dn, uacTrustedToAuthForDelegation, d.Get("protocol_transition").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("principals_allowed_to_delegate") {
+		if err := client.updateAllowedToActOnBehalfOf(dn, expandStringSet(d.Get("principals_allowed_to_delegate"))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPKerberosDelegationRead(ctx, d, m)
+}
+
+func resourceLDAPKerberosDelegationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewModifyRequest(dn, nil)
+	req.Replace("msDS-AllowedToDelegateTo", []string{})
+	req.Replace("msDS-AllowedToActOnBehalfOfOtherIdentity", []string{})
+
+	err := client.Conn.Modify(req)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.setUserAccountControlFlag(dn, uacTrustedToAuthForDelegation, false)
+
+	return diag.FromErr(err)
+}
+
+// updateAllowedToActOnBehalfOf replaces the principals allowed to delegate to the
+// account, removing msDS-AllowedToActOnBehalfOfOtherIdentity when there are none
+func (c *providerClient) updateAllowedToActOnBehalfOf(dn string, principals []string) error {
+	if len(principals) == 0 {
+		return c.replaceAttribute(dn, "msDS-AllowedToActOnBehalfOfOtherIdentity", []string{})
+	}
+
+	encoded, err := c.encodePrincipals(principals)
+	if err != nil {
+		return err
+	}
+
+	return c.replaceAttribute(dn, "msDS-AllowedToActOnBehalfOfOtherIdentity", []string{string(encoded)})
+}
@@ -0,0 +1,274 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// managedServiceAccountReadAttributes are the attributes read for a gMSA
+var managedServiceAccountReadAttributes = []string{
+	"name",
+	"sAMAccountName",
+	"objectSid",
+	"dNSHostName",
+	"description",
+	"servicePrincipalName",
+	"msDS-ManagedPasswordInterval",
+	"msDS-GroupMSAMembership",
+}
+
+func resourceLDAPManagedServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_managed_service_account` is a resource for managing an Active Directory group Managed Service Account (gMSA).",
+		CreateContext: resourceLDAPManagedServiceAccountCreate,
+		ReadContext:   resourceLDAPManagedServiceAccountRead,
+		UpdateContext: resourceLDAPManagedServiceAccountUpdate,
+		DeleteContext: resourceLDAPManagedServiceAccountDelete,
+		CustomizeDiff: customdiff.All(
+			resourceLDAPManagedServiceAccountCustomizeDiff,
+			customizeDiffCheckWriteAllowed("CN"),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the gMSA.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"ou": {
+				Description: "OU where the gMSA will be created. Defaults to the Managed Service Accounts container of the domain.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Description:  "gMSA name, its sAMAccountName is the name followed by `$`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 15),
+			},
+			"dns_host_name": {
+				Description: "The DNS host name of the gMSA.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"description": {
+				Description: "Description attribute for the gMSA.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"service_principal_names": {
+				Description: "Service principal names of the gMSA. Left unmanaged if not set.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"password_interval": {
+				Description:  "Number of days after which the gMSA password is changed. It can only be set at creation. Default is `30`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"principals_allowed_to_retrieve_password": {
+				Description: "DNs of the groups and computers allowed to retrieve the gMSA password.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sam_account_name": {
+				Description: "The sAMAccountName of the gMSA.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"object_sid": {
+				Description: "The SID of the gMSA.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// resourceLDAPManagedServiceAccountCustomizeDiff defaults ou to the Managed Service
+// Accounts container of the domain, so the gMSA DN is known and checked at plan time
+func resourceLDAPManagedServiceAccountCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Only an ou left unset in the configuration of a new gMSA is defaulted
+	config := d.GetRawConfig()
+	if d.Id() != "" || config.IsNull() || !config.GetAttr("ou").IsNull() {
+		return nil
+	}
+
+	baseDN, err := client.baseDNOrDefault("")
+	if err != nil {
+		return err
+	}
+
+	return d.SetNew("ou", "CN=Managed Service Accounts,"+baseDN)
+}
+
+func resourceLDAPManagedServiceAccountCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	name := d.Get("name").(string)
+	dn := fmt.Sprintf("CN=%s,%s", name, d.Get("ou").(string))
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	membership, err := client.encodePrincipals(expandStringSet(d.Get("principals_allowed_to_retrieve_password")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"msDS-GroupManagedServiceAccount"})
+	req.Attribute("sAMAccountName", []string{name + "$"})
+	req.Attribute("userAccountControl", []string{strconv.Itoa(uacWorkstationTrustAccount)})
+	req.Attribute("dNSHostName", []string{d.Get("dns_host_name").(string)})
+	req.Attribute("msDS-ManagedPasswordInterval", []string{strconv.Itoa(d.Get("password_interval").(int))})
+	req.Attribute("msDS-GroupMSAMembership", []string{string(membership)})
+
+	if description := d.Get("description").(string); description != "" {
+		req.Attribute("description", []string{description})
+	}
+
+	if spns := expandStringSet(d.Get("service_principal_names")); len(spns) > 0 {
+		req.Attribute("servicePrincipalName", spns)
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPManagedServiceAccountRead(ctx, d, m)
+}
+
+func resourceLDAPManagedServiceAccountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, managedServiceAccountReadAttributes)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	ou, err := parentDN(dn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sid, err := decodeSID(entry.GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return diag.Errorf("failed decoding objectSid of %s: %s", dn, err)
+	}
+
+	passwordInterval, err := parseIntAttribute(entry, "msDS-ManagedPasswordInterval")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	principals, err := client.decodePrincipals(entry.GetRawAttributeValue("msDS-GroupMSAMembership"))
+	if err != nil {
+		return diag.Errorf("failed decoding msDS-GroupMSAMembership of %s: %s", dn, err)
+	}
+
+	values := map[string]interface{}{
+		"name":                    entry.GetAttributeValue("name"),
+		"ou":                      ou,
+		"dns_host_name":           entry.GetAttributeValue("dNSHostName"),
+		"description":             entry.GetAttributeValue("description"),
+		"service_principal_names": entry.GetAttributeValues("servicePrincipalName"),
+		"password_interval":       passwordInterval,
+		"principals_allowed_to_retrieve_password": keepConfiguredDNs(principals, d.Get("principals_allowed_to_retrieve_password")),
+		"sam_account_name":                        entry.GetAttributeValue("sAMAccountName"),
+		"object_sid":                              sid,
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPManagedServiceAccountUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := updateStringAttributes(client, dn, d, map[string]string{
+		"dns_host_name": "dNSHostName",
+		"description":   "description",
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("service_principal_names") {
+		if err := client.replaceAttribute(dn, "servicePrincipalName", expandStringSet(d.Get("service_principal_names"))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("principals_allowed_to_retrieve_password") {
+		membership, err := client.encodePrincipals(expandStringSet(d.Get("principals_allowed_to_retrieve_password")))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := client.replaceAttribute(dn, "msDS-GroupMSAMembership", []string{string(membership)}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPManagedServiceAccountRead(ctx, d, m)
+}
+
+func resourceLDAPManagedServiceAccountDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.Conn.Del(ldap.NewDelRequest(dn, nil))
+
+	return diag.FromErr(err)
+}
@@ -5,12 +5,27 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Ouest-France/goldap"
 	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// ouReservedAttributes are the LDAP attributes managed by the
+// dedicated arguments of ldap_ou, which can't be set in attributes
+var ouReservedAttributes = []string{
+	"ou",
+	"name",
+	"distinguishedName",
+	"objectClass",
+	"description",
+	"managedBy",
+	"gPLink",
+	"gPOptions",
+	"nTSecurityDescriptor",
+}
+
 func resourceLDAPOU() *schema.Resource {
 	return &schema.Resource{
 		Description:   "`ldap_ou` is a resource for managing an LDAP OU.",
@@ -18,6 +33,12 @@ func resourceLDAPOU() *schema.Resource {
 		ReadContext:   resourceLDAPOURead,
 		UpdateContext: resourceLDAPOUUpdate,
 		DeleteContext: resourceLDAPOUDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffCheckWriteAllowed("OU"),
+			customizeDiffCheckParentsWriteAllowed,
+			customizeDiffCheckAttributes(ouReservedAttributes),
+			resourceLDAPOUCustomizeDiff,
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -41,37 +62,195 @@ func resourceLDAPOU() *schema.Resource {
 				ForceNew:    true,
 			},
 			"description": {
-				Description: "Description attribute for the LDAP OU.",
-				Type:        schema.TypeString,
-				Optional:    true,
+				Description:      "Description attribute for the LDAP OU.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("description"),
 			},
 			"managed_by": {
-				Description: "ManagedBy attribute",
+				Description:      "ManagedBy attribute",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("managedBy"),
+			},
+			"gpo_links": gpoLinksResourceSchema("OU"),
+			"block_inheritance": {
+				Description:      "Block the inheritance of the Group Policy links of the parent OUs. Default is `false`.",
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: ignoredAttributeDiffSuppress("gPOptions"),
+			},
+			"create_parents": {
+				Description: "Create the missing OUs in the path of `ou` when creating the LDAP OU. Only the OUs created this way are deleted when destroying the OU, and only if they are empty. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"created_parents": {
+				Description: "DNs of the parent OUs created because of `create_parents`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"attributes":        attributesResourceSchema("OU"),
+			"ignore_attributes": ignoreAttributesSchema("OU"),
+			"adopt_existing": {
+				Description: "Adopt the LDAP OU if it already exists when creating it, instead of failing. Its attributes are then reconciled with the configuration. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"deletion_protection": {
+				Description: "Prevent the LDAP OU from being destroyed. It must be set to `false` and applied before the OU can be destroyed. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"protect_from_accidental_deletion": {
+				Description: "Set the Active Directory \"Protect object from accidental deletion\" deny ACE on the LDAP OU, protecting it from deletion outside of Terraform too. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete_strategy": {
+				Description:  "How to handle objects left in the LDAP OU when destroying it: `fail` reports the objects blocking the deletion, `tree_delete` deletes the OU with all its content using the tree delete control, `move_children_to` moves the OU content to the OU set in `move_children_to` before deleting it. Default is `fail`.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"fail", "tree_delete", "move_children_to"}, false),
+			},
+			"move_children_to": {
+				Description: "DN of the OU where the LDAP OU content is moved before destroying it, required when `delete_strategy` is `move_children_to`.",
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
 			},
 		},
 	}
 }
 
+func resourceLDAPOUCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("delete_strategy").(string) == "move_children_to" && d.NewValueKnown("move_children_to") && d.Get("move_children_to").(string) == "" {
+		return fmt.Errorf("move_children_to must be set when delete_strategy is \"move_children_to\"")
+	}
+
+	return nil
+}
+
 func resourceLDAPOUCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	dn := fmt.Sprintf("OU=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
-	err := client.CreateOrganizationalUnit(dn, d.Get("description").(string), d.Get("managed_by").(string))
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	adopt, err := adoptExistingEntry(client, d, dn, "organizationalUnit")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if adopt {
+		if err := resourceLDAPOUReconcile(client, dn, d); err != nil {
+			return diag.FromErr(err)
+		}
+		diags = append(diags, adoptionWarning("OU", dn))
+	} else {
+		if err := createParents(client, d); err != nil {
+			return diag.FromErr(err)
+		}
+
+		err := client.CreateOrganizationalUnit(dn, d.Get("description").(string), d.Get("managed_by").(string))
+		if err != nil {
+			return diag.FromErr(cleanupCreatedParents(client, d, err))
+		}
+
+		if v, ok := d.GetOk("gpo_links"); ok {
+			links, err := client.expandGPOLinks(v)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if err := client.updateGPOLinks(dn, links); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		if d.Get("block_inheritance").(bool) {
+			if err := client.updateBlockInheritance(dn, true); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err := client.updateAttributes(dn, nil, expandAttributes(d.Get("attributes"))); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(dn)
 
-	return resourceLDAPOURead(ctx, d, m)
+	if d.Get("protect_from_accidental_deletion").(bool) {
+		if err := client.setAccidentalDeletionProtection(dn, true); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return append(diags, resourceLDAPOURead(ctx, d, m)...)
+}
+
+// resourceLDAPOUReconcile updates the attributes of an adopted LDAP OU
+// which differ from the configuration
+func resourceLDAPOUReconcile(client *providerClient, dn string, d *schema.ResourceData) error {
+	attributes, err := client.ReadOrganizationalUnit(dn)
+	if err != nil {
+		return err
+	}
+
+	if description := d.Get("description").(string); !isIgnoredAttribute(d, "description") && description != firstAttributeValue(attributes, "description") {
+		if err := client.UpdateOrganizationalUnitDescription(dn, description); err != nil {
+			return err
+		}
+	}
+
+	if managedBy := d.Get("managed_by").(string); !isIgnoredAttribute(d, "managedBy") && managedBy != firstAttributeValue(attributes, "managedBy") {
+		if err := client.UpdateOrganizationalUnitManagedBy(dn, managedBy); err != nil {
+			return err
+		}
+	}
+
+	links, blockInheritance, err := client.readGPOLinks(dn)
+	if err != nil {
+		return err
+	}
+
+	if !isIgnoredAttribute(d, "gPLink") {
+		newLinks, err := client.expandGPOLinks(d.Get("gpo_links"))
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(formatGPLink(newLinks), formatGPLink(links)) {
+			if err := client.updateGPOLinks(dn, newLinks); err != nil {
+				return err
+			}
+		}
+	}
+
+	if newBlockInheritance := d.Get("block_inheritance").(bool); !isIgnoredAttribute(d, "gPOptions") && newBlockInheritance != blockInheritance {
+		if err := client.updateBlockInheritance(dn, newBlockInheritance); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func resourceLDAPOURead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 
 	dn := d.Id()
 
@@ -124,13 +303,43 @@ func resourceLDAPOURead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
+	links, blockInheritance, err := client.readGPOLinks(dn)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("gpo_links", flattenGPOLinks(links)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("block_inheritance", blockInheritance); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := readExtraAttributes(ctx, client, dn, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Accidental deletion protection is only exposed on the resource
+	if ctx.Value(CallerTypeKey) != DatasourceCaller {
+		protected, err := client.readAccidentalDeletionProtection(dn)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("protect_from_accidental_deletion", protected); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return diag.FromErr(err)
 }
 
 func resourceLDAPOUUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 	dn := fmt.Sprintf("OU=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if d.HasChange("description") {
 		if err := client.UpdateOrganizationalUnitDescription(dn, d.Get("description").(string)); err != nil {
 			return diag.FromErr(err)
@@ -143,14 +352,142 @@ func resourceLDAPOUUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
+	if d.HasChange("gpo_links") {
+		links, err := client.expandGPOLinks(d.Get("gpo_links"))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := client.updateGPOLinks(dn, links); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("block_inheritance") {
+		if err := client.updateBlockInheritance(dn, d.Get("block_inheritance").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("attributes") {
+		old, new := d.GetChange("attributes")
+		if err := client.updateAttributes(dn, expandAttributes(old), expandAttributes(new)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("protect_from_accidental_deletion") {
+		if err := client.setAccidentalDeletionProtection(dn, d.Get("protect_from_accidental_deletion").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceLDAPOURead(ctx, d, m)
 }
 
 func resourceLDAPOUDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*goldap.Client)
+	client := m.(*providerClient)
 	dn := fmt.Sprintf("OU=%s,%s", d.Get("name").(string), d.Get("ou").(string))
 
-	err := client.DeleteOrganizationalUnit(dn)
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("LDAP OU %s has deletion_protection enabled, set it to false and apply before destroying it", dn)
+	}
+
+	strategy := d.Get("delete_strategy").(string)
+	switch strategy {
+	case "tree_delete":
+		if err := client.checkSubtreeDeleteAllowed(dn); err != nil {
+			return diag.FromErr(err)
+		}
+	case "fail":
+		// Nothing is changed on the OU if it can't be deleted
+		children, err := client.searchChildrenDNs(dn)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(children) > 0 {
+			return ouNotEmptyDiagnostics(dn, children)
+		}
+	}
+
+	// The Active Directory protection managed by Terraform is removed to allow the deletion
+	err := client.deleteProtectedObject(dn, d.Get("protect_from_accidental_deletion").(bool), func() error {
+		switch strategy {
+		case "tree_delete":
+			return client.Conn.Del(ldap.NewDelRequest(dn, []ldap.Control{ldap.NewControlSubtreeDelete()}))
+		case "move_children_to":
+			if err := moveOUChildren(client, dn, d.Get("move_children_to").(string)); err != nil {
+				return err
+			}
+		}
+
+		return client.DeleteOrganizationalUnit(dn)
+	})
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+		// Children were added since the check, report the objects blocking the deletion
+		children, searchErr := client.searchChildrenDNs(dn)
+		if searchErr != nil {
+			return diag.FromErr(err)
+		}
+
+		return ouNotEmptyDiagnostics(dn, children)
+	}
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteCreatedParents(client, d)
 
 	return diag.FromErr(err)
 }
+
+// ouNotEmptyDiagnostics reports the children preventing the deletion of the OU
+func ouNotEmptyDiagnostics(dn string, children []string) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("LDAP OU %s is not empty", dn),
+			Detail: fmt.Sprintf(
+				"The following objects must be removed before destroying the OU, or delete_strategy must be set to \"tree_delete\" or \"move_children_to\":\n%s",
+				strings.Join(children, "\n"),
+			),
+		},
+	}
+}
+
+// moveOUChildren moves all the direct children of the OU to the target OU
+func moveOUChildren(client *providerClient, dn, target string) error {
+	if target == "" {
+		return fmt.Errorf("move_children_to must be set when delete_strategy is \"move_children_to\"")
+	}
+
+	children, err := client.searchChildrenDNs(dn)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		parsedChild, err := ldap.ParseDN(child)
+		if err != nil {
+			return fmt.Errorf("failed parsing DN %q: %w", child, err)
+		}
+		rdn := parsedChild.RDNs[0].String()
+
+		if err := client.checkWriteAllowed(child); err != nil {
+			return err
+		}
+		if err := client.checkWriteAllowed(fmt.Sprintf("%s,%s", rdn, target)); err != nil {
+			return err
+		}
+
+		if err := client.Conn.ModifyDN(ldap.NewModifyDNRequest(child, rdn, true, target)); err != nil {
+			return fmt.Errorf("failed moving %q to %q: %w", child, target, err)
+		}
+	}
+
+	return nil
+}
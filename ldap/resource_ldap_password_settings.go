@@ -0,0 +1,439 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// passwordSettingsIntAttributes maps the ldap_password_settings integer arguments to their LDAP attribute
+var passwordSettingsIntAttributes = map[string]string{
+	"precedence":              "msDS-PasswordSettingsPrecedence",
+	"min_password_length":     "msDS-MinimumPasswordLength",
+	"password_history_length": "msDS-PasswordHistoryLength",
+	"lockout_threshold":       "msDS-LockoutThreshold",
+}
+
+// passwordSettingsBoolAttributes maps the ldap_password_settings boolean arguments to their LDAP attribute
+var passwordSettingsBoolAttributes = map[string]string{
+	"password_complexity":   "msDS-PasswordComplexityEnabled",
+	"reversible_encryption": "msDS-PasswordReversibleEncryptionEnabled",
+}
+
+// passwordSettingsDurationAttributes maps the ldap_password_settings duration arguments to their LDAP attribute
+var passwordSettingsDurationAttributes = map[string]string{
+	"min_password_age":           "msDS-MinimumPasswordAge",
+	"max_password_age":           "msDS-MaximumPasswordAge",
+	"lockout_duration":           "msDS-LockoutDuration",
+	"lockout_observation_window": "msDS-LockoutObservationWindow",
+}
+
+func resourceLDAPPasswordSettings() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_password_settings` is a resource for managing an Active Directory fine-grained password policy (Password Settings Object).",
+		CreateContext: resourceLDAPPasswordSettingsCreate,
+		ReadContext:   resourceLDAPPasswordSettingsRead,
+		UpdateContext: resourceLDAPPasswordSettingsUpdate,
+		DeleteContext: resourceLDAPPasswordSettingsDelete,
+		CustomizeDiff: resourceLDAPPasswordSettingsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the Password Settings Object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description: "Password Settings Object name, it is created in the Password Settings Container of the domain.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Description: "Description attribute for the Password Settings Object.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"precedence": {
+				Description:  "The precedence of the policy when several apply to a user: the lowest value wins.",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"min_password_length": {
+				Description:  "The minimum password length. Default is `7`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      7,
+				ValidateFunc: validation.IntBetween(0, 255),
+			},
+			"password_history_length": {
+				Description:  "The number of remembered passwords which can't be reused. Default is `24`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      24,
+				ValidateFunc: validation.IntBetween(0, 1024),
+			},
+			"password_complexity": {
+				Description: "Whether passwords must meet complexity requirements. Default is `true`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"reversible_encryption": {
+				Description: "Whether passwords are stored using reversible encryption. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"min_password_age": {
+				Description:      "The minimum password age as a duration (e.g. `24h`). Default is `24h`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "24h",
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"max_password_age": {
+				Description:      "The maximum password age as a duration (e.g. `1008h`), `0` if passwords never expire. Default is `1008h` (42 days).",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "1008h",
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"lockout_threshold": {
+				Description:  "The number of failed logons before an account is locked out, `0` if accounts are never locked out. Default is `0`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 65535),
+			},
+			"lockout_duration": {
+				Description:      "The lockout duration as a duration, `0` if locked out accounts must be unlocked by an administrator. Default is `30m`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "30m",
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"lockout_observation_window": {
+				Description:      "The duration after which the failed logons counter is reset. Default is `30m`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "30m",
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"applies_to": {
+				Description: "DNs of the groups and users the policy applies to (msDS-PSOAppliesTo).",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// validateDuration validates a non negative Go duration string
+func validateDuration(v interface{}, k string) ([]string, []error) {
+	if _, err := durationToADInterval(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+
+	return nil, nil
+}
+
+// durationDiffSuppress ignores the differences between two notations of the same duration (e.g. `24h` and `24h0m0s`)
+func durationDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	oldDuration, err := time.ParseDuration(old)
+	if err != nil {
+		return false
+	}
+	newDuration, err := time.ParseDuration(new)
+	if err != nil {
+		return false
+	}
+
+	return oldDuration == newDuration
+}
+
+// resourceLDAPPasswordSettingsCustomizeDiff rejects at plan time the durations
+// Active Directory would refuse, and changes on a Password Settings Object whose
+// DN is forbidden by the provider configuration
+func resourceLDAPPasswordSettingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := checkPasswordSettingsDurations(d); err != nil {
+		return err
+	}
+
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	// Nothing will be written if the plan is empty
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	if d.Id() != "" && !d.HasChange("name") {
+		return client.checkWriteAllowed(d.Id())
+	}
+
+	if !d.NewValueKnown("name") {
+		return nil
+	}
+
+	dn, err := client.passwordSettingsDN(d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+
+	return client.checkWriteAllowed(dn)
+}
+
+// checkPasswordSettingsDurations checks that the minimum password age is lower than
+// the maximum one, and that the lockout observation window isn't longer than the
+// lockout duration, a zero maximum age or lockout duration meaning "never"
+func checkPasswordSettingsDurations(d *schema.ResourceDiff) error {
+	durations := map[string]time.Duration{}
+	for argument := range passwordSettingsDurationAttributes {
+		if !d.NewValueKnown(argument) {
+			continue
+		}
+		duration, err := time.ParseDuration(d.Get(argument).(string))
+		if err != nil {
+			continue
+		}
+		durations[argument] = duration
+	}
+
+	minAge, minAgeKnown := durations["min_password_age"]
+	maxAge, maxAgeKnown := durations["max_password_age"]
+	if minAgeKnown && maxAgeKnown && maxAge != 0 && minAge >= maxAge {
+		return fmt.Errorf("min_password_age (%s) must be lower than max_password_age (%s)", minAge, maxAge)
+	}
+
+	window, windowKnown := durations["lockout_observation_window"]
+	lockout, lockoutKnown := durations["lockout_duration"]
+	if windowKnown && lockoutKnown && lockout != 0 && window > lockout {
+		return fmt.Errorf("lockout_observation_window (%s) must not be longer than lockout_duration (%s)", window, lockout)
+	}
+
+	return nil
+}
+
+// passwordSettingsDN returns the DN of the Password Settings Object with the given
+// name, in the Password Settings Container of the domain
+func (c *providerClient) passwordSettingsDN(name string) (string, error) {
+	baseDN, err := c.baseDNOrDefault("")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("CN=%s,CN=Password Settings Container,CN=System,%s", name, baseDN), nil
+}
+
+// expandPasswordSettingsDuration converts a duration argument to its AD interval
+func expandPasswordSettingsDuration(d *schema.ResourceData, argument string) (string, error) {
+	value := d.Get(argument).(string)
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q: %w", argument, value, err)
+	}
+	if duration == 0 && passwordPolicyNeverArguments[argument] {
+		return strconv.FormatInt(adIntervalNever, 10), nil
+	}
+
+	return durationToADInterval(value)
+}
+
+func resourceLDAPPasswordSettingsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn, err := client.passwordSettingsDN(d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"msDS-PasswordSettings"})
+
+	for argument, ldapAttribute := range passwordSettingsIntAttributes {
+		req.Attribute(ldapAttribute, []string{strconv.Itoa(d.Get(argument).(int))})
+	}
+
+	for argument, ldapAttribute := range passwordSettingsBoolAttributes {
+		req.Attribute(ldapAttribute, []string{strings.ToUpper(strconv.FormatBool(d.Get(argument).(bool)))})
+	}
+
+	for argument, ldapAttribute := range passwordSettingsDurationAttributes {
+		interval, err := expandPasswordSettingsDuration(d, argument)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.Attribute(ldapAttribute, []string{interval})
+	}
+
+	if description := d.Get("description").(string); description != "" {
+		req.Attribute("description", []string{description})
+	}
+
+	if appliesTo := expandStringSet(d.Get("applies_to")); len(appliesTo) > 0 {
+		req.Attribute("msDS-PSOAppliesTo", appliesTo)
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPPasswordSettingsRead(ctx, d, m)
+}
+
+func resourceLDAPPasswordSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	attributes := []string{"name", "description", "msDS-PSOAppliesTo"}
+	for _, ldapAttribute := range passwordSettingsIntAttributes {
+		attributes = append(attributes, ldapAttribute)
+	}
+	for _, ldapAttribute := range passwordSettingsBoolAttributes {
+		attributes = append(attributes, ldapAttribute)
+	}
+	for _, ldapAttribute := range passwordSettingsDurationAttributes {
+		attributes = append(attributes, ldapAttribute)
+	}
+
+	entry, err := client.readEntry(dn, attributes)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name":        entry.GetAttributeValue("name"),
+		"description": entry.GetAttributeValue("description"),
+		"applies_to":  keepConfiguredDNs(entry.GetAttributeValues("msDS-PSOAppliesTo"), d.Get("applies_to")),
+	}
+
+	for argument, ldapAttribute := range passwordSettingsIntAttributes {
+		value, err := parseIntAttribute(entry, ldapAttribute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		values[argument] = value
+	}
+
+	for argument, ldapAttribute := range passwordSettingsBoolAttributes {
+		values[argument] = strings.EqualFold(entry.GetAttributeValue(ldapAttribute), "TRUE")
+	}
+
+	for argument, ldapAttribute := range passwordSettingsDurationAttributes {
+		value, err := flattenPasswordPolicyDuration(argument, entry.GetAttributeValue(ldapAttribute))
+		if err != nil {
+			return diag.Errorf("failed converting %s of %s: %s", ldapAttribute, dn, err)
+		}
+		values[argument] = value
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPPasswordSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewModifyRequest(dn, nil)
+
+	for argument, ldapAttribute := range passwordSettingsIntAttributes {
+		if d.HasChange(argument) {
+			req.Replace(ldapAttribute, []string{strconv.Itoa(d.Get(argument).(int))})
+		}
+	}
+
+	for argument, ldapAttribute := range passwordSettingsBoolAttributes {
+		if d.HasChange(argument) {
+			req.Replace(ldapAttribute, []string{strings.ToUpper(strconv.FormatBool(d.Get(argument).(bool)))})
+		}
+	}
+
+	for argument, ldapAttribute := range passwordSettingsDurationAttributes {
+		if d.HasChange(argument) {
+			interval, err := expandPasswordSettingsDuration(d, argument)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			req.Replace(ldapAttribute, []string{interval})
+		}
+	}
+
+	if d.HasChange("description") {
+		values := []string{}
+		if description := d.Get("description").(string); description != "" {
+			values = append(values, description)
+		}
+		req.Replace("description", values)
+	}
+
+	if d.HasChange("applies_to") {
+		req.Replace("msDS-PSOAppliesTo", expandStringSet(d.Get("applies_to")))
+	}
+
+	// The lockout and age settings are checked against each other by Active
+	// Directory, so they are all replaced in a single request
+	if len(req.Changes) > 0 {
+		if err := client.Conn.Modify(req); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPPasswordSettingsRead(ctx, d, m)
+}
+
+func resourceLDAPPasswordSettingsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.Conn.Del(ldap.NewDelRequest(dn, nil))
+
+	return diag.FromErr(err)
+}
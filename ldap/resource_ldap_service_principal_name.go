@@ -0,0 +1,228 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceLDAPServicePrincipalName() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_service_principal_name` is a resource for managing service principal names of an existing LDAP account, non-authoritatively.",
+		CreateContext: resourceLDAPServicePrincipalNameCreate,
+		ReadContext:   resourceLDAPServicePrincipalNameRead,
+		UpdateContext: resourceLDAPServicePrincipalNameUpdate,
+		DeleteContext: resourceLDAPServicePrincipalNameDelete,
+		CustomizeDiff: resourceLDAPServicePrincipalNameCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the LDAP account.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"account_dn": {
+				Description: "The DN of the LDAP user or computer account.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"service_principal_names": {
+				Description: "Service principal names managed on the LDAP account. Other values of the account are left untouched. The plan fails if a value is already registered on another account of the forest.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceLDAPServicePrincipalNameCustomizeDiff rejects at plan time the service
+// principal names already registered on another account of the forest
+func resourceLDAPServicePrincipalNameCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client, ok := m.(*providerClient)
+	if !ok || client == nil {
+		return nil
+	}
+
+	if !d.NewValueKnown("account_dn") || !d.NewValueKnown("service_principal_names") {
+		return nil
+	}
+
+	accountDN := d.Get("account_dn").(string)
+	if err := client.checkWriteAllowed(accountDN); err != nil {
+		return err
+	}
+
+	old, new := d.GetChange("service_principal_names")
+	added := new.(*schema.Set).Difference(old.(*schema.Set))
+	if d.HasChange("account_dn") {
+		added = new.(*schema.Set)
+	}
+
+	parsedAccountDN, err := ldap.ParseDN(accountDN)
+	if err != nil {
+		return fmt.Errorf("failed parsing DN %q: %w", accountDN, err)
+	}
+
+	for _, spn := range expandStringSet(added) {
+		filter := fmt.Sprintf("(servicePrincipalName=%s)", ldap.EscapeFilter(spn))
+		entries, err := client.searchGlobalCatalog(filter, []string{"distinguishedName"})
+		if err != nil {
+			return fmt.Errorf("failed searching duplicates of service principal name %q: %w", spn, err)
+		}
+
+		for _, entry := range entries {
+			entryDN, err := ldap.ParseDN(entry.DN)
+			if err != nil || !entryDN.EqualFold(parsedAccountDN) {
+				return fmt.Errorf("service principal name %q is already registered on %q", spn, entry.DN)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPServicePrincipalNameCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Get("account_dn").(string)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := client.updateServicePrincipalNames(dn, expandStringSet(d.Get("service_principal_names")), nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPServicePrincipalNameRead(ctx, d, m)
+}
+
+func resourceLDAPServicePrincipalNameRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"servicePrincipalName"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Account doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	current := map[string]string{}
+	for _, spn := range entry.GetAttributeValues("servicePrincipalName") {
+		current[strings.ToLower(spn)] = spn
+	}
+
+	// Only the managed values still registered on the account are kept, with the configured case
+	spns := []string{}
+	for _, spn := range expandStringSet(d.Get("service_principal_names")) {
+		if _, ok := current[strings.ToLower(spn)]; ok {
+			spns = append(spns, spn)
+		}
+	}
+
+	if err := d.Set("account_dn", dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("service_principal_names", spns)
+
+	return diag.FromErr(err)
+}
+
+func resourceLDAPServicePrincipalNameUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("service_principal_names") {
+		old, new := d.GetChange("service_principal_names")
+		added := expandStringSet(new.(*schema.Set).Difference(old.(*schema.Set)))
+		removed := expandStringSet(old.(*schema.Set).Difference(new.(*schema.Set)))
+
+		if err := client.updateServicePrincipalNames(dn, added, removed); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPServicePrincipalNameRead(ctx, d, m)
+}
+
+func resourceLDAPServicePrincipalNameDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.updateServicePrincipalNames(dn, nil, expandStringSet(d.Get("service_principal_names")))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil
+	}
+
+	return diag.FromErr(err)
+}
+
+// updateServicePrincipalNames adds and removes service principal names of the account,
+// leaving its other values untouched. Values already added or already removed are skipped.
+func (c *providerClient) updateServicePrincipalNames(dn string, added, removed []string) error {
+	entry, err := c.readEntry(dn, []string{"servicePrincipalName"})
+	if err != nil {
+		return err
+	}
+
+	current := map[string]string{}
+	for _, spn := range entry.GetAttributeValues("servicePrincipalName") {
+		current[strings.ToLower(spn)] = spn
+	}
+
+	toAdd := []string{}
+	for _, spn := range added {
+		if _, ok := current[strings.ToLower(spn)]; !ok {
+			toAdd = append(toAdd, spn)
+		}
+	}
+
+	// Values are removed with the case registered on the account
+	toDelete := []string{}
+	for _, spn := range removed {
+		if existing, ok := current[strings.ToLower(spn)]; ok {
+			toDelete = append(toDelete, existing)
+		}
+	}
+
+	req := ldap.NewModifyRequest(dn, nil)
+	if len(toAdd) > 0 {
+		req.Add("servicePrincipalName", toAdd)
+	}
+	if len(toDelete) > 0 {
+		req.Delete("servicePrincipalName", toDelete)
+	}
+
+	if len(req.Changes) == 0 {
+		return nil
+	}
+
+	return c.Conn.Modify(req)
+}
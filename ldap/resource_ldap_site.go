@@ -0,0 +1,198 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// siteStringAttributes maps the ldap_site single valued arguments to their LDAP attribute
+var siteStringAttributes = map[string]string{
+	"description": "description",
+	"location":    "location",
+}
+
+func resourceLDAPSite() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_site` is a resource for managing an Active Directory site.",
+		CreateContext: resourceLDAPSiteCreate,
+		ReadContext:   resourceLDAPSiteRead,
+		UpdateContext: resourceLDAPSiteUpdate,
+		DeleteContext: resourceLDAPSiteDelete,
+		CustomizeDiff: customizeDiffCheckSitesWriteAllowed(""),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the site.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description: "The site name.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Description: "Description attribute for the site.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"location": {
+				Description: "The location of the site.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"subnets": {
+				Description: "DNs of the subnets assigned to the site.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"deletion_protection": {
+				Description: "Prevent the site from being destroyed. It must be set to `false` and applied before the site can be destroyed. Default is `false`.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceLDAPSiteCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	sitesDN, err := client.sitesContainerDN("")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), sitesDN)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"site"})
+
+	for attribute, ldapAttribute := range siteStringAttributes {
+		if value := d.Get(attribute).(string); value != "" {
+			req.Attribute(ldapAttribute, []string{value})
+		}
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	// Like Active Directory Sites and Services, create the site settings used by
+	// the KCC and the container of the domain controllers of the site
+	children := map[string]string{
+		"NTDS Site Settings": "nTDSSiteSettings",
+		"Servers":            "serversContainer",
+	}
+	for name, objectClass := range children {
+		req := ldap.NewAddRequest(fmt.Sprintf("CN=%s,%s", name, dn), nil)
+		req.Attribute("objectClass", []string{objectClass})
+
+		if err := client.Conn.Add(req); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPSiteRead(ctx, d, m)
+}
+
+func resourceLDAPSiteRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"name", "description", "location", "siteObjectBL"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name":    entry.GetAttributeValue("name"),
+		"subnets": entry.GetAttributeValues("siteObjectBL"),
+	}
+	for attribute, ldapAttribute := range siteStringAttributes {
+		values[attribute] = entry.GetAttributeValue(ldapAttribute)
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPSiteUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := updateStringAttributes(client, dn, d, siteStringAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceLDAPSiteRead(ctx, d, m)
+}
+
+func resourceLDAPSiteDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkSubtreeDeleteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("site %s has deletion_protection enabled, set it to false and apply before destroying it", dn)
+	}
+
+	// The tree delete must not remove domain controllers, they have to be moved to another site first
+	servers, err := client.searchChildrenDNs("CN=Servers," + dn)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return diag.FromErr(err)
+	}
+	if len(servers) > 0 {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("site %s is not empty", dn),
+				Detail: fmt.Sprintf(
+					"The following servers must be moved to another site before destroying the site:\n%s",
+					strings.Join(servers, "\n"),
+				),
+			},
+		}
+	}
+
+	err = client.Conn.Del(ldap.NewDelRequest(dn, []ldap.Control{ldap.NewControlSubtreeDelete()}))
+
+	return diag.FromErr(err)
+}
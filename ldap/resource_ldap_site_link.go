@@ -0,0 +1,195 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLDAPSiteLink() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_site_link` is a resource for managing an Active Directory IP site link.",
+		CreateContext: resourceLDAPSiteLinkCreate,
+		ReadContext:   resourceLDAPSiteLinkRead,
+		UpdateContext: resourceLDAPSiteLinkUpdate,
+		DeleteContext: resourceLDAPSiteLinkDelete,
+		CustomizeDiff: customizeDiffCheckSitesWriteAllowed(sitesIPLinksContainer),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the site link.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description: "The site link name.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"sites": {
+				Description: "DNs of the sites connected by the site link.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    2,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"cost": {
+				Description:  "The cost of the site link, the replication topology prefers the links with the lowest cost. Default is `100`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntBetween(1, 99999),
+			},
+			"replication_interval": {
+				Description:  "The replication interval of the site link in minutes, a multiple of 15. Default is `180`.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      180,
+				ValidateFunc: validation.All(validation.IntBetween(15, 10080), validation.IntDivisibleBy(15)),
+			},
+			"description": {
+				Description: "Description attribute for the site link.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceLDAPSiteLinkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	linksDN, err := client.sitesContainerDN(sitesIPLinksContainer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), linksDN)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"siteLink"})
+	req.Attribute("siteList", expandStringSet(d.Get("sites")))
+	req.Attribute("cost", []string{strconv.Itoa(d.Get("cost").(int))})
+	req.Attribute("replInterval", []string{strconv.Itoa(d.Get("replication_interval").(int))})
+
+	if description := d.Get("description").(string); description != "" {
+		req.Attribute("description", []string{description})
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPSiteLinkRead(ctx, d, m)
+}
+
+func resourceLDAPSiteLinkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"name", "siteList", "cost", "replInterval", "description"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	cost, err := parseIntAttribute(entry, "cost")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	replicationInterval, err := parseIntAttribute(entry, "replInterval")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name":                 entry.GetAttributeValue("name"),
+		"sites":                keepConfiguredDNs(entry.GetAttributeValues("siteList"), d.Get("sites")),
+		"cost":                 cost,
+		"replication_interval": replicationInterval,
+		"description":          entry.GetAttributeValue("description"),
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPSiteLinkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewModifyRequest(dn, nil)
+
+	if d.HasChange("sites") {
+		req.Replace("siteList", expandStringSet(d.Get("sites")))
+	}
+
+	if d.HasChange("cost") {
+		req.Replace("cost", []string{strconv.Itoa(d.Get("cost").(int))})
+	}
+
+	if d.HasChange("replication_interval") {
+		req.Replace("replInterval", []string{strconv.Itoa(d.Get("replication_interval").(int))})
+	}
+
+	if d.HasChange("description") {
+		values := []string{}
+		if description := d.Get("description").(string); description != "" {
+			values = append(values, description)
+		}
+		req.Replace("description", values)
+	}
+
+	if len(req.Changes) > 0 {
+		if err := client.Conn.Modify(req); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceLDAPSiteLinkRead(ctx, d, m)
+}
+
+func resourceLDAPSiteLinkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.Conn.Del(ldap.NewDelRequest(dn, nil))
+
+	return diag.FromErr(err)
+}
@@ -0,0 +1,158 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// subnetStringAttributes maps the ldap_subnet single valued arguments to their LDAP attribute
+var subnetStringAttributes = map[string]string{
+	"site":        "siteObject",
+	"description": "description",
+	"location":    "location",
+}
+
+func resourceLDAPSubnet() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`ldap_subnet` is a resource for managing an Active Directory subnet and its site assignment.",
+		CreateContext: resourceLDAPSubnetCreate,
+		ReadContext:   resourceLDAPSubnetRead,
+		UpdateContext: resourceLDAPSubnetUpdate,
+		DeleteContext: resourceLDAPSubnetDelete,
+		CustomizeDiff: customizeDiffCheckSitesWriteAllowed(sitesSubnetsContainer),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Description: "The DN of the subnet.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description:  "The subnet prefix in CIDR notation (e.g. `10.1.0.0/16`).",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDRNetwork(0, 128),
+			},
+			"site": {
+				Description: "The DN of the site the subnet is assigned to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"description": {
+				Description: "Description attribute for the subnet.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"location": {
+				Description: "The location of the subnet.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceLDAPSubnetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	subnetsDN, err := client.sitesContainerDN(sitesSubnetsContainer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dn := fmt.Sprintf("CN=%s,%s", d.Get("name").(string), subnetsDN)
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	req := ldap.NewAddRequest(dn, nil)
+	req.Attribute("objectClass", []string{"subnet"})
+
+	for attribute, ldapAttribute := range subnetStringAttributes {
+		if value := d.Get(attribute).(string); value != "" {
+			req.Attribute(ldapAttribute, []string{value})
+		}
+	}
+
+	if err := client.Conn.Add(req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(dn)
+
+	return resourceLDAPSubnetRead(ctx, d, m)
+}
+
+func resourceLDAPSubnetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+
+	dn := d.Id()
+
+	entry, err := client.readEntry(dn, []string{"name", "siteObject", "description", "location"})
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			// Object doesn't exist, remove the resource from the state
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name": entry.GetAttributeValue("name"),
+	}
+	for attribute, ldapAttribute := range subnetStringAttributes {
+		values[attribute] = entry.GetAttributeValue(ldapAttribute)
+	}
+
+	// Active Directory returns the site DN in its own case
+	if site := d.Get("site").(string); dnEqualFold(site, values["site"].(string)) {
+		values["site"] = site
+	}
+
+	for attribute, value := range values {
+		if err := d.Set(attribute, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceLDAPSubnetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := updateStringAttributes(client, dn, d, subnetStringAttributes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceLDAPSubnetRead(ctx, d, m)
+}
+
+func resourceLDAPSubnetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerClient)
+	dn := d.Id()
+
+	if err := client.checkWriteAllowed(dn); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := client.Conn.Del(ldap.NewDelRequest(dn, nil))
+
+	return diag.FromErr(err)
+}
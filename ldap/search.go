@@ -0,0 +1,286 @@
+package ldap
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// searchPageSize is the page size used for paged searches
+const searchPageSize = 500
+
+// capabilityActiveDirectory is the LDAP_CAP_ACTIVE_DIRECTORY_OID capability advertised in the root DSE
+const capabilityActiveDirectory = "1.2.840.113556.1.4.800"
+
+// matchingRuleInChain is the LDAP_MATCHING_RULE_IN_CHAIN OID used for transitive searches
+const matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+// search runs a paged search returning the given attributes of the entries matching filter
+func (c *providerClient) search(baseDN string, scope int, filter string, attributes []string) ([]*ldap.Entry, error) {
+	req := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	result, err := c.Conn.SearchWithPaging(req, searchPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Entries, nil
+}
+
+// searchGlobalCatalog searches the whole forest through the Global Catalog, which
+// holds a partial replica of the objects of all the domains. The Global Catalog
+// connection is only opened by the first search.
+func (c *providerClient) searchGlobalCatalog(filter string, attributes []string) ([]*ldap.Entry, error) {
+	c.globalCatalogMutex.Lock()
+	defer c.globalCatalogMutex.Unlock()
+
+	if c.globalCatalog.Conn == nil {
+		if err := c.globalCatalog.Connect(); err != nil {
+			c.globalCatalog.Conn = nil
+			return nil, fmt.Errorf("failed connecting to the Global Catalog %s:%d: %w", c.globalCatalog.Host, c.globalCatalog.Port, err)
+		}
+	}
+
+	// The empty base DN covers all the naming contexts of the forest
+	req := ldap.NewSearchRequest(
+		"",
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	result, err := c.globalCatalog.Conn.SearchWithPaging(req, searchPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Entries, nil
+}
+
+// searchChildrenDNs returns the DNs of the direct children of the given DN
+func (c *providerClient) searchChildrenDNs(dn string) ([]string, error) {
+	entries, err := c.search(dn, ldap.ScopeSingleLevel, "(objectClass=*)", []string{"distinguishedName"})
+	if err != nil {
+		return nil, err
+	}
+
+	dns := []string{}
+	for _, entry := range entries {
+		dns = append(dns, entry.DN)
+	}
+
+	return dns, nil
+}
+
+// readEntry reads the given attributes of the entry at the given DN
+func (c *providerClient) readEntry(dn string, attributes []string) (*ldap.Entry, error) {
+	req := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		attributes,
+		nil,
+	)
+
+	result, err := c.Conn.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Entries) != 1 {
+		return nil, fmt.Errorf("expected one entry for %q, got %d", dn, len(result.Entries))
+	}
+
+	return result.Entries[0], nil
+}
+
+// entryExists returns true if an entry exists at the given DN
+func (c *providerClient) entryExists(dn string) (bool, error) {
+	_, err := c.readEntry(dn, []string{"distinguishedName"})
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// countAttributeValues returns the number of values of an attribute of an entry.
+// The values are read by ranges, so attributes with more values than the server
+// MaxValRange limit, like the members of large groups, are counted in full.
+func (c *providerClient) countAttributeValues(dn, attribute string) (int, error) {
+	prefix := strings.ToLower(attribute) + ";range="
+
+	count := 0
+	low := 0
+	for {
+		entry, err := c.readEntry(dn, []string{fmt.Sprintf("%s;range=%d-*", attribute, low)})
+		if err != nil {
+			return 0, err
+		}
+
+		var values *ldap.EntryAttribute
+		for _, entryAttribute := range entry.Attributes {
+			if strings.HasPrefix(strings.ToLower(entryAttribute.Name), prefix) {
+				values = entryAttribute
+			}
+		}
+
+		// Servers not supporting range retrieval return the attribute as is
+		if values == nil {
+			return count + len(entry.GetAttributeValues(attribute)), nil
+		}
+		count += len(values.Values)
+
+		// The returned range is "<low>-<high>", or "<low>-*" for the last one
+		bounds := strings.SplitN(values.Name[len(prefix):], "-", 2)
+		if len(bounds) != 2 {
+			return 0, fmt.Errorf("invalid range %q returned for %s of %q", values.Name, attribute, dn)
+		}
+		if bounds[1] == "*" {
+			return count, nil
+		}
+
+		high, err := strconv.Atoi(bounds[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid range %q returned for %s of %q", values.Name, attribute, dn)
+		}
+		low = high + 1
+	}
+}
+
+// hasObjectClass returns true if the entry has the given objectClass
+func hasObjectClass(entry *ldap.Entry, objectClass string) bool {
+	for _, value := range entry.GetAttributeValues("objectClass") {
+		if strings.EqualFold(value, objectClass) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readRootDSE reads the given attributes of the root DSE
+func (c *providerClient) readRootDSE(attributes []string) (*ldap.Entry, error) {
+	return c.readEntry("", attributes)
+}
+
+// baseDNOrDefault returns the given base DN, or the default naming context if it is empty
+func (c *providerClient) baseDNOrDefault(baseDN string) (string, error) {
+	if baseDN != "" {
+		return baseDN, nil
+	}
+
+	rootDSE, err := c.readRootDSE([]string{"defaultNamingContext"})
+	if err != nil {
+		return "", err
+	}
+
+	return rootDSE.GetAttributeValue("defaultNamingContext"), nil
+}
+
+// isActiveDirectory returns true if the server advertises the Active Directory capability
+func (c *providerClient) isActiveDirectory() (bool, error) {
+	rootDSE, err := c.readRootDSE([]string{"supportedCapabilities"})
+	if err != nil {
+		return false, err
+	}
+
+	for _, capability := range rootDSE.GetAttributeValues("supportedCapabilities") {
+		if capability == capabilityActiveDirectory {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// escapeBinaryFilter escapes every byte of a binary value for use in a search filter
+func escapeBinaryFilter(value []byte) string {
+	var b strings.Builder
+	for _, v := range value {
+		fmt.Fprintf(&b, "\\%02x", v)
+	}
+
+	return b.String()
+}
+
+// searchBySIDs searches the entries with the given string SIDs below baseDN, in batches
+func (c *providerClient) searchBySIDs(baseDN string, sids []string, attributes []string) ([]*ldap.Entry, error) {
+	const batchSize = 50
+
+	entries := []*ldap.Entry{}
+	for start := 0; start < len(sids); start += batchSize {
+		end := start + batchSize
+		if end > len(sids) {
+			end = len(sids)
+		}
+
+		filter := "(|"
+		for _, sid := range sids[start:end] {
+			binarySID, err := encodeSID(sid)
+			if err != nil {
+				return nil, err
+			}
+			filter += fmt.Sprintf("(objectSid=%s)", escapeBinaryFilter(binarySID))
+		}
+		filter += ")"
+
+		batch, err := c.search(baseDN, ldap.ScopeWholeSubtree, filter, attributes)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, batch...)
+	}
+
+	return entries, nil
+}
+
+// buildListFilter combines the object filter with an optional name prefix and raw filter
+func buildListFilter(objectFilter, namePrefix, rawFilter string) string {
+	filter := "(&" + objectFilter
+
+	if namePrefix != "" {
+		filter += fmt.Sprintf("(name=%s*)", ldap.EscapeFilter(namePrefix))
+	}
+
+	if rawFilter != "" {
+		if !strings.HasPrefix(rawFilter, "(") {
+			rawFilter = fmt.Sprintf("(%s)", rawFilter)
+		}
+		filter += rawFilter
+	}
+
+	return filter + ")"
+}
+
+// sortEntriesByDN sorts entries by case insensitive DN for a deterministic ordering
+func sortEntriesByDN(entries []*ldap.Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].DN) < strings.ToLower(entries[j].DN)
+	})
+}
@@ -0,0 +1,643 @@
+package ldap
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Security descriptor control flags
+const (
+	sdControlDACLPresent   uint16 = 0x0004
+	sdControlSACLPresent   uint16 = 0x0010
+	sdControlDACLProtected uint16 = 0x1000
+	sdControlSelfRelative  uint16 = 0x8000
+)
+
+// ACE types
+const (
+	aceTypeAccessAllowed       byte = 0x00
+	aceTypeAccessDenied        byte = 0x01
+	aceTypeSystemAudit         byte = 0x02
+	aceTypeAccessAllowedObject byte = 0x05
+	aceTypeAccessDeniedObject  byte = 0x06
+	aceTypeSystemAuditObject   byte = 0x07
+)
+
+// ACE flags
+const (
+	aceFlagObjectInherit    byte = 0x01
+	aceFlagContainerInherit byte = 0x02
+	aceFlagNoPropagate      byte = 0x04
+	aceFlagInheritOnly      byte = 0x08
+	aceFlagInherited        byte = 0x10
+)
+
+// Object ACE flags
+const (
+	aceObjectTypePresent          uint32 = 0x1
+	aceInheritedObjectTypePresent uint32 = 0x2
+)
+
+// Access rights
+const (
+	rightDSCreateChild   uint32 = 0x00000001
+	rightDSDeleteChild   uint32 = 0x00000002
+	rightDSListChildren  uint32 = 0x00000004
+	rightDSSelf          uint32 = 0x00000008
+	rightDSReadProperty  uint32 = 0x00000010
+	rightDSWriteProperty uint32 = 0x00000020
+	rightDSDeleteTree    uint32 = 0x00000040
+	rightDSListObject    uint32 = 0x00000080
+	rightDSControlAccess uint32 = 0x00000100
+	rightDelete          uint32 = 0x00010000
+	rightReadControl     uint32 = 0x00020000
+	rightWriteDAC        uint32 = 0x00040000
+	rightWriteOwner      uint32 = 0x00080000
+	rightFullControl     uint32 = 0x000F01FF
+)
+
+// SD flags control values, selecting which parts of the security descriptor are read or written
+const (
+	sdFlagsOwner uint32 = 0x1
+	sdFlagsDACL  uint32 = 0x4
+)
+
+// controlTypeSDFlags is the OID of the LDAP_SERVER_SD_FLAGS_OID control
+const controlTypeSDFlags = "1.2.840.113556.1.4.801"
+
+// sidEveryone is the well-known SID of the Everyone group
+const sidEveryone = "S-1-1-0"
+
+// sidBuiltinAdministrators is the well-known SID of the BUILTIN\Administrators group
+const sidBuiltinAdministrators = "S-1-5-32-544"
+
+// securityDescriptor is a decoded self-relative Windows security descriptor
+type securityDescriptor struct {
+	Revision byte
+	Control  uint16
+	Owner    string
+	Group    string
+	SACL     *acl
+	DACL     *acl
+}
+
+// acl is a decoded Windows access control list
+type acl struct {
+	Revision byte
+	ACEs     []ace
+}
+
+// ace is a decoded Windows access control entry. Object ACE specific fields
+// are empty for non-object ACEs. ACEs of unsupported types are kept in Raw
+// so they can be written back unchanged.
+type ace struct {
+	Type                byte
+	Flags               byte
+	Mask                uint32
+	ObjectType          string
+	InheritedObjectType string
+	SID                 string
+	Raw                 []byte
+}
+
+// isObjectACE returns true if the ACE type carries object type GUIDs
+func (a ace) isObjectACE() bool {
+	return a.Type == aceTypeAccessAllowedObject || a.Type == aceTypeAccessDeniedObject || a.Type == aceTypeSystemAuditObject
+}
+
+// isDeny returns true if the ACE is a deny ACE
+func (a ace) isDeny() bool {
+	return a.Type == aceTypeAccessDenied || a.Type == aceTypeAccessDeniedObject
+}
+
+// equal compares two ACEs ignoring the SID and GUID case
+func (a ace) equal(other ace) bool {
+	if a.Raw != nil || other.Raw != nil {
+		return false
+	}
+
+	return a.Type == other.Type &&
+		a.Flags == other.Flags &&
+		a.Mask == other.Mask &&
+		strings.EqualFold(a.ObjectType, other.ObjectType) &&
+		strings.EqualFold(a.InheritedObjectType, other.InheritedObjectType) &&
+		strings.EqualFold(a.SID, other.SID)
+}
+
+// decodeSID converts a binary SID to its string form (S-1-5-21-...)
+func decodeSID(b []byte) (string, error) {
+	if len(b) < 8 {
+		return "", fmt.Errorf("SID too short: %d bytes", len(b))
+	}
+
+	subAuthorityCount := int(b[1])
+	if len(b) < 8+4*subAuthorityCount {
+		return "", fmt.Errorf("SID too short for %d sub-authorities: %d bytes", subAuthorityCount, len(b))
+	}
+
+	authority := uint64(0)
+	for _, v := range b[2:8] {
+		authority = authority<<8 | uint64(v)
+	}
+
+	sid := fmt.Sprintf("S-%d-%d", b[0], authority)
+	for i := 0; i < subAuthorityCount; i++ {
+		sid += fmt.Sprintf("-%d", binary.LittleEndian.Uint32(b[8+4*i:]))
+	}
+
+	return sid, nil
+}
+
+// sidLength returns the length of the binary SID at the beginning of b
+func sidLength(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+
+	return 8 + 4*int(b[1])
+}
+
+// encodeSID converts a string SID (S-1-5-21-...) to its binary form
+func encodeSID(sid string) ([]byte, error) {
+	parts := strings.Split(strings.ToUpper(sid), "-")
+	if len(parts) < 3 || parts[0] != "S" {
+		return nil, fmt.Errorf("invalid SID %q", sid)
+	}
+
+	revision, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SID %q revision: %w", sid, err)
+	}
+
+	authority, err := strconv.ParseUint(parts[2], 10, 48)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SID %q authority: %w", sid, err)
+	}
+
+	subAuthorities := parts[3:]
+	if len(subAuthorities) > 15 {
+		return nil, fmt.Errorf("invalid SID %q: too many sub-authorities", sid)
+	}
+
+	b := make([]byte, 8+4*len(subAuthorities))
+	b[0] = byte(revision)
+	b[1] = byte(len(subAuthorities))
+	for i := 0; i < 6; i++ {
+		b[7-i] = byte(authority >> (8 * i))
+	}
+	for i, subAuthority := range subAuthorities {
+		v, err := strconv.ParseUint(subAuthority, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SID %q sub-authority: %w", sid, err)
+		}
+		binary.LittleEndian.PutUint32(b[8+4*i:], uint32(v))
+	}
+
+	return b, nil
+}
+
+// decodeGUID converts a binary GUID in Microsoft mixed-endian
+// layout to its string form (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)
+func decodeGUID(b []byte) (string, error) {
+	if len(b) != 16 {
+		return "", fmt.Errorf("invalid GUID length: %d bytes", len(b))
+	}
+
+	return fmt.Sprintf("%08x-%04x-%04x-%s-%s",
+		binary.LittleEndian.Uint32(b[0:4]),
+		binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]),
+		hex.EncodeToString(b[8:10]),
+		hex.EncodeToString(b[10:16]),
+	), nil
+}
+
+// encodeGUID converts a string GUID to its binary Microsoft mixed-endian layout
+func encodeGUID(guid string) ([]byte, error) {
+	raw, err := hex.DecodeString(strings.ReplaceAll(strings.Trim(guid, "{}"), "-", ""))
+	if err != nil || len(raw) != 16 {
+		return nil, fmt.Errorf("invalid GUID %q", guid)
+	}
+
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint32(b[0:4], binary.BigEndian.Uint32(raw[0:4]))
+	binary.LittleEndian.PutUint16(b[4:6], binary.BigEndian.Uint16(raw[4:6]))
+	binary.LittleEndian.PutUint16(b[6:8], binary.BigEndian.Uint16(raw[6:8]))
+	copy(b[8:], raw[8:])
+
+	return b, nil
+}
+
+// decodeSecurityDescriptor decodes a self-relative security descriptor
+// as returned in the nTSecurityDescriptor attribute
+func decodeSecurityDescriptor(b []byte) (*securityDescriptor, error) {
+	if len(b) < 20 {
+		return nil, fmt.Errorf("security descriptor too short: %d bytes", len(b))
+	}
+
+	sd := &securityDescriptor{
+		Revision: b[0],
+		Control:  binary.LittleEndian.Uint16(b[2:4]),
+	}
+
+	ownerOffset := binary.LittleEndian.Uint32(b[4:8])
+	groupOffset := binary.LittleEndian.Uint32(b[8:12])
+	saclOffset := binary.LittleEndian.Uint32(b[12:16])
+	daclOffset := binary.LittleEndian.Uint32(b[16:20])
+
+	var err error
+	if ownerOffset != 0 {
+		if int(ownerOffset) >= len(b) {
+			return nil, fmt.Errorf("invalid security descriptor owner offset %d", ownerOffset)
+		}
+		if sd.Owner, err = decodeSID(b[ownerOffset:]); err != nil {
+			return nil, fmt.Errorf("failed decoding security descriptor owner: %w", err)
+		}
+	}
+
+	if groupOffset != 0 {
+		if int(groupOffset) >= len(b) {
+			return nil, fmt.Errorf("invalid security descriptor group offset %d", groupOffset)
+		}
+		if sd.Group, err = decodeSID(b[groupOffset:]); err != nil {
+			return nil, fmt.Errorf("failed decoding security descriptor group: %w", err)
+		}
+	}
+
+	if saclOffset != 0 && sd.Control&sdControlSACLPresent != 0 {
+		if sd.SACL, err = decodeACL(b, saclOffset); err != nil {
+			return nil, fmt.Errorf("failed decoding security descriptor SACL: %w", err)
+		}
+	}
+
+	if daclOffset != 0 && sd.Control&sdControlDACLPresent != 0 {
+		if sd.DACL, err = decodeACL(b, daclOffset); err != nil {
+			return nil, fmt.Errorf("failed decoding security descriptor DACL: %w", err)
+		}
+	}
+
+	return sd, nil
+}
+
+// decodeACL decodes the ACL found at the given offset of a security descriptor
+func decodeACL(b []byte, offset uint32) (*acl, error) {
+	if int(offset)+8 > len(b) {
+		return nil, fmt.Errorf("invalid ACL offset %d", offset)
+	}
+
+	header := b[offset:]
+	size := int(binary.LittleEndian.Uint16(header[2:4]))
+	count := int(binary.LittleEndian.Uint16(header[4:6]))
+	if size < 8 || size > len(header) {
+		return nil, fmt.Errorf("invalid ACL size %d", size)
+	}
+
+	list := &acl{Revision: header[0], ACEs: []ace{}}
+
+	data := header[8:size]
+	for i := 0; i < count; i++ {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("ACE %d truncated", i)
+		}
+
+		aceSize := int(binary.LittleEndian.Uint16(data[2:4]))
+		if aceSize < 8 || aceSize > len(data) {
+			return nil, fmt.Errorf("invalid ACE %d size %d", i, aceSize)
+		}
+
+		entry, err := decodeACE(data[:aceSize])
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding ACE %d: %w", i, err)
+		}
+		list.ACEs = append(list.ACEs, entry)
+
+		data = data[aceSize:]
+	}
+
+	return list, nil
+}
+
+// decodeACE decodes a single ACE, keeping ACEs of unsupported types as raw bytes
+func decodeACE(b []byte) (ace, error) {
+	entry := ace{
+		Type:  b[0],
+		Flags: b[1],
+		Mask:  binary.LittleEndian.Uint32(b[4:8]),
+	}
+
+	body := b[8:]
+	switch entry.Type {
+	case aceTypeAccessAllowed, aceTypeAccessDenied, aceTypeSystemAudit:
+	case aceTypeAccessAllowedObject, aceTypeAccessDeniedObject, aceTypeSystemAuditObject:
+		if len(body) < 4 {
+			return entry, fmt.Errorf("object ACE truncated")
+		}
+		objectFlags := binary.LittleEndian.Uint32(body[0:4])
+		body = body[4:]
+
+		var err error
+		if objectFlags&aceObjectTypePresent != 0 {
+			if len(body) < 16 {
+				return entry, fmt.Errorf("object ACE object type truncated")
+			}
+			if entry.ObjectType, err = decodeGUID(body[:16]); err != nil {
+				return entry, err
+			}
+			body = body[16:]
+		}
+		if objectFlags&aceInheritedObjectTypePresent != 0 {
+			if len(body) < 16 {
+				return entry, fmt.Errorf("object ACE inherited object type truncated")
+			}
+			if entry.InheritedObjectType, err = decodeGUID(body[:16]); err != nil {
+				return entry, err
+			}
+			body = body[16:]
+		}
+	default:
+		entry.Raw = append([]byte{}, b...)
+		return entry, nil
+	}
+
+	length := sidLength(body)
+	if length == 0 || length > len(body) {
+		return entry, fmt.Errorf("ACE SID truncated")
+	}
+
+	sid, err := decodeSID(body[:length])
+	if err != nil {
+		return entry, err
+	}
+	entry.SID = sid
+
+	// Keep ACEs carrying application data as raw to write them back unchanged
+	if length < len(body) {
+		entry.Raw = append([]byte{}, b...)
+	}
+
+	return entry, nil
+}
+
+// encode encodes the security descriptor in self-relative format
+func (sd *securityDescriptor) encode() ([]byte, error) {
+	control := sd.Control | sdControlSelfRelative
+	control &^= sdControlDACLPresent | sdControlSACLPresent
+
+	b := make([]byte, 20)
+	b[0] = sd.Revision
+	if b[0] == 0 {
+		b[0] = 1
+	}
+
+	if sd.SACL != nil {
+		encoded, err := sd.SACL.encode()
+		if err != nil {
+			return nil, err
+		}
+		control |= sdControlSACLPresent
+		binary.LittleEndian.PutUint32(b[12:16], uint32(len(b)))
+		b = append(b, encoded...)
+	}
+
+	if sd.DACL != nil {
+		encoded, err := sd.DACL.encode()
+		if err != nil {
+			return nil, err
+		}
+		control |= sdControlDACLPresent
+		binary.LittleEndian.PutUint32(b[16:20], uint32(len(b)))
+		b = append(b, encoded...)
+	}
+
+	if sd.Owner != "" {
+		encoded, err := encodeSID(sd.Owner)
+		if err != nil {
+			return nil, err
+		}
+		binary.LittleEndian.PutUint32(b[4:8], uint32(len(b)))
+		b = append(b, encoded...)
+	}
+
+	if sd.Group != "" {
+		encoded, err := encodeSID(sd.Group)
+		if err != nil {
+			return nil, err
+		}
+		binary.LittleEndian.PutUint32(b[8:12], uint32(len(b)))
+		b = append(b, encoded...)
+	}
+
+	binary.LittleEndian.PutUint16(b[2:4], control)
+
+	return b, nil
+}
+
+// encode encodes the ACL and its ACEs
+func (l *acl) encode() ([]byte, error) {
+	revision := byte(2)
+	if l.Revision > revision {
+		revision = l.Revision
+	}
+
+	body := []byte{}
+	for _, entry := range l.ACEs {
+		if entry.isObjectACE() {
+			revision = 4
+		}
+
+		encoded, err := entry.encode()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, encoded...)
+	}
+
+	b := make([]byte, 8, 8+len(body))
+	b[0] = revision
+	binary.LittleEndian.PutUint16(b[2:4], uint16(8+len(body)))
+	binary.LittleEndian.PutUint16(b[4:6], uint16(len(l.ACEs)))
+
+	return append(b, body...), nil
+}
+
+// encode encodes a single ACE
+func (a ace) encode() ([]byte, error) {
+	if a.Raw != nil {
+		return a.Raw, nil
+	}
+
+	sid, err := encodeSID(a.SID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte{}
+	if a.isObjectACE() {
+		objectFlags := uint32(0)
+		guids := []byte{}
+
+		if a.ObjectType != "" {
+			guid, err := encodeGUID(a.ObjectType)
+			if err != nil {
+				return nil, err
+			}
+			objectFlags |= aceObjectTypePresent
+			guids = append(guids, guid...)
+		}
+
+		if a.InheritedObjectType != "" {
+			guid, err := encodeGUID(a.InheritedObjectType)
+			if err != nil {
+				return nil, err
+			}
+			objectFlags |= aceInheritedObjectTypePresent
+			guids = append(guids, guid...)
+		}
+
+		body = make([]byte, 4, 4+len(guids))
+		binary.LittleEndian.PutUint32(body, objectFlags)
+		body = append(body, guids...)
+	}
+	body = append(body, sid...)
+
+	b := make([]byte, 8, 8+len(body))
+	b[0] = a.Type
+	b[1] = a.Flags
+	binary.LittleEndian.PutUint16(b[2:4], uint16(8+len(body)))
+	binary.LittleEndian.PutUint32(b[4:8], a.Mask)
+
+	return append(b, body...), nil
+}
+
+// addACE adds an ACE to the ACL in canonical order: explicit deny ACEs first,
+// then explicit allow ACEs, then inherited ACEs. Nothing is done if an equal
+// ACE already exists.
+func (l *acl) addACE(entry ace) {
+	for _, existing := range l.ACEs {
+		if existing.equal(entry) {
+			return
+		}
+	}
+
+	position := len(l.ACEs)
+	for i, existing := range l.ACEs {
+		if existing.Flags&aceFlagInherited != 0 || (entry.isDeny() && !existing.isDeny()) {
+			position = i
+			break
+		}
+	}
+
+	l.ACEs = append(l.ACEs, ace{})
+	copy(l.ACEs[position+1:], l.ACEs[position:])
+	l.ACEs[position] = entry
+}
+
+// removeACE removes all ACEs equal to the given one from the ACL
+// and returns true if any ACE was removed
+func (l *acl) removeACE(entry ace) bool {
+	removed := false
+	aces := []ace{}
+	for _, existing := range l.ACEs {
+		if existing.equal(entry) {
+			removed = true
+			continue
+		}
+		aces = append(aces, existing)
+	}
+	l.ACEs = aces
+
+	return removed
+}
+
+// hasACE returns true if the ACL contains an ACE equal to the given one
+func (l *acl) hasACE(entry ace) bool {
+	for _, existing := range l.ACEs {
+		if existing.equal(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sdFlagsControl returns the SD flags control selecting the given security descriptor parts
+func sdFlagsControl(flags uint32) ldap.Control {
+	// BER encoding of SEQUENCE { INTEGER flags } with a 4 bytes integer
+	value := []byte{0x30, 0x06, 0x02, 0x04, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(value[4:], flags)
+
+	return ldap.NewControlString(controlTypeSDFlags, true, string(value))
+}
+
+// readSecurityDescriptor reads the parts of the object security descriptor selected by flags
+func (c *providerClient) readSecurityDescriptor(dn string, flags uint32) (*securityDescriptor, error) {
+	req := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		"(objectClass=*)",
+		[]string{"nTSecurityDescriptor"},
+		[]ldap.Control{sdFlagsControl(flags)},
+	)
+
+	result, err := c.Conn.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Entries) != 1 {
+		return nil, fmt.Errorf("expected one entry for %q, got %d", dn, len(result.Entries))
+	}
+
+	raw := result.Entries[0].GetRawAttributeValue("nTSecurityDescriptor")
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no nTSecurityDescriptor returned for %q, check the bind user permissions", dn)
+	}
+
+	return decodeSecurityDescriptor(raw)
+}
+
+// writeSecurityDescriptor writes the parts of the object security descriptor selected by flags
+func (c *providerClient) writeSecurityDescriptor(dn string, sd *securityDescriptor, flags uint32) error {
+	encoded, err := sd.encode()
+	if err != nil {
+		return fmt.Errorf("failed encoding security descriptor for %q: %w", dn, err)
+	}
+
+	req := ldap.NewModifyRequest(dn, []ldap.Control{sdFlagsControl(flags)})
+	req.Replace("nTSecurityDescriptor", []string{string(encoded)})
+
+	return c.Conn.Modify(req)
+}
+
+// securityDescriptorMutex serializes the security descriptor updates, as resources
+// applied in parallel may update the security descriptor of the same object
+var securityDescriptorMutex sync.Mutex
+
+// updateSecurityDescriptor reads the parts of the object security descriptor selected
+// by flags, applies update and writes the security descriptor back if update returns true
+func (c *providerClient) updateSecurityDescriptor(dn string, flags uint32, update func(sd *securityDescriptor) (bool, error)) error {
+	securityDescriptorMutex.Lock()
+	defer securityDescriptorMutex.Unlock()
+
+	sd, err := c.readSecurityDescriptor(dn, flags)
+	if err != nil {
+		return err
+	}
+
+	changed, err := update(sd)
+	if err != nil || !changed {
+		return err
+	}
+
+	return c.writeSecurityDescriptor(dn, sd, flags)
+}
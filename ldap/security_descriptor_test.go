@@ -0,0 +1,257 @@
+package ldap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mustDecodeHex decodes a hex fixture, ignoring the spaces used to group its fields
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatalf("invalid hex fixture: %s", err)
+	}
+
+	return b
+}
+
+func TestSID(t *testing.T) {
+	cases := []struct {
+		sid string
+		hex string
+	}{
+		{"S-1-1-0", "01 01 000000000001 00000000"},
+		{"S-1-5-18", "01 01 000000000005 12000000"},
+		{"S-1-5-32-544", "01 02 000000000005 20000000 20020000"},
+		{"S-1-5-21-3623811015-3361044348-30300820-1013", "01 05 000000000005 15000000 c7f7fed7 7c7755c8 945ace01 f5030000"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.sid, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			sid, err := decodeSID(b)
+			if err != nil {
+				t.Fatalf("decodeSID: %s", err)
+			}
+			if sid != c.sid {
+				t.Errorf("decodeSID: got %s, want %s", sid, c.sid)
+			}
+
+			encoded, err := encodeSID(c.sid)
+			if err != nil {
+				t.Fatalf("encodeSID: %s", err)
+			}
+			if !bytes.Equal(encoded, b) {
+				t.Errorf("encodeSID: got %x, want %x", encoded, b)
+			}
+
+			if length := sidLength(b); length != len(b) {
+				t.Errorf("sidLength: got %d, want %d", length, len(b))
+			}
+		})
+	}
+}
+
+func TestSIDInvalid(t *testing.T) {
+	for _, sid := range []string{"", "S-1", "X-1-5-18", "S-1-5-abc", "S-1-5-1-2-3-4-5-6-7-8-9-10-11-12-13-14-15-16"} {
+		if _, err := encodeSID(sid); err == nil {
+			t.Errorf("encodeSID(%q): expected an error", sid)
+		}
+	}
+
+	if _, err := decodeSID(mustDecodeHex(t, "01 02 000000000005 20000000")); err == nil {
+		t.Errorf("decodeSID: expected an error for a truncated SID")
+	}
+}
+
+func TestGUID(t *testing.T) {
+	cases := []struct {
+		guid string
+		hex  string
+	}{
+		// User-Force-Change-Password extended right
+		{"00299570-246d-11d0-a768-00aa006e0529", "70952900 6d24 d011 a768 00aa006e0529"},
+		// user schema class
+		{"bf967aba-0de6-11d0-a285-00aa003049e2", "ba7a96bf e60d d011 a285 00aa003049e2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.guid, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			guid, err := decodeGUID(b)
+			if err != nil {
+				t.Fatalf("decodeGUID: %s", err)
+			}
+			if guid != c.guid {
+				t.Errorf("decodeGUID: got %s, want %s", guid, c.guid)
+			}
+
+			for _, value := range []string{c.guid, strings.ToUpper(c.guid), "{" + c.guid + "}"} {
+				encoded, err := encodeGUID(value)
+				if err != nil {
+					t.Fatalf("encodeGUID(%q): %s", value, err)
+				}
+				if !bytes.Equal(encoded, b) {
+					t.Errorf("encodeGUID(%q): got %x, want %x", value, encoded, b)
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityDescriptor(t *testing.T) {
+	cases := []struct {
+		name string
+		hex  string
+		sd   *securityDescriptor
+	}{
+		{
+			name: "DACL with object ACE",
+			hex: "" +
+				// Header: revision 1, control SE_SELF_RELATIVE|SE_DACL_PRESENT,
+				// owner at 0x80, group at 0x90, no SACL, DACL at 0x14
+				"01 00 0480 80000000 90000000 00000000 14000000" +
+				// DACL: revision 4, size 0x6c, 3 ACEs
+				"04 00 6c00 0300 0000" +
+				// (D;;SDDT;;;WD)
+				"01 00 1400 40000100 010100000000000100000000" +
+				// (OA;CI;CR;00299570-246d-11d0-a768-00aa006e0529;bf967aba-0de6-11d0-a285-00aa003049e2;BA)
+				"05 02 3c00 00010000 03000000 709529006d24d011a76800aa006e0529 ba7a96bfe60dd011a28500aa003049e2 01020000000000052000000020020000" +
+				// (A;ID;GA;;;SY) as full control
+				"00 10 1400 ff010f00 010100000000000512000000" +
+				// Owner BA, group SY
+				"01020000000000052000000020020000" +
+				"010100000000000512000000",
+			sd: &securityDescriptor{
+				Revision: 1,
+				Control:  sdControlSelfRelative | sdControlDACLPresent,
+				Owner:    sidBuiltinAdministrators,
+				Group:    "S-1-5-18",
+				DACL: &acl{
+					Revision: 4,
+					ACEs: []ace{
+						{Type: aceTypeAccessDenied, Mask: rightDelete | rightDSDeleteTree, SID: sidEveryone},
+						{
+							Type:                aceTypeAccessAllowedObject,
+							Flags:               aceFlagContainerInherit,
+							Mask:                rightDSControlAccess,
+							ObjectType:          "00299570-246d-11d0-a768-00aa006e0529",
+							InheritedObjectType: "bf967aba-0de6-11d0-a285-00aa003049e2",
+							SID:                 sidBuiltinAdministrators,
+						},
+						{Type: aceTypeAccessAllowed, Flags: aceFlagInherited, Mask: rightFullControl, SID: "S-1-5-18"},
+					},
+				},
+			},
+		},
+		{
+			name: "protected DACL with unsupported ACE",
+			hex: "" +
+				// Header: revision 1, control SE_SELF_RELATIVE|SE_DACL_PROTECTED|SE_DACL_PRESENT,
+				// owner at 0x44, no group, no SACL, DACL at 0x14
+				"01 00 0490 44000000 00000000 00000000 14000000" +
+				// DACL: revision 2, size 0x30, 2 ACEs
+				"02 00 3000 0200 0000" +
+				// (A;;RC;;;AU)
+				"00 00 1400 00000200 01010000000000050b000000" +
+				// Mandatory label ACE, kept as is
+				"11 00 1400 01000000 010100000000001000100000" +
+				// Owner SY
+				"010100000000000512000000",
+			sd: &securityDescriptor{
+				Revision: 1,
+				Control:  sdControlSelfRelative | sdControlDACLProtected | sdControlDACLPresent,
+				Owner:    "S-1-5-18",
+				DACL: &acl{
+					Revision: 2,
+					ACEs: []ace{
+						{Type: aceTypeAccessAllowed, Mask: rightReadControl, SID: "S-1-5-11"},
+						{
+							Type: 0x11,
+							Mask: 0x1,
+							Raw:  mustDecodeHex(t, "11 00 1400 01000000 010100000000001000100000"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := mustDecodeHex(t, c.hex)
+
+			sd, err := decodeSecurityDescriptor(b)
+			if err != nil {
+				t.Fatalf("decodeSecurityDescriptor: %s", err)
+			}
+			if !reflect.DeepEqual(sd, c.sd) {
+				t.Errorf("decodeSecurityDescriptor: got %+v, want %+v", sd, c.sd)
+			}
+
+			encoded, err := c.sd.encode()
+			if err != nil {
+				t.Fatalf("encode: %s", err)
+			}
+			if !bytes.Equal(encoded, b) {
+				t.Errorf("encode: got %x, want %x", encoded, b)
+			}
+		})
+	}
+}
+
+func TestSecurityDescriptorInvalid(t *testing.T) {
+	cases := map[string]string{
+		"too short":         "01 00 0480 00000000",
+		"owner offset":      "01 00 0480 ff000000 00000000 00000000 00000000",
+		"ACL size":          "01 00 0480 00000000 00000000 00000000 14000000 02 00 ff00 0000 0000",
+		"ACE size":          "01 00 0480 00000000 00000000 00000000 14000000 02 00 1000 0100 0000 00 00 ff00 00000000",
+		"ACE SID truncated": "01 00 0480 00000000 00000000 00000000 14000000 02 00 1400 0100 0000 00 00 0c00 00000200 0101 0000",
+		"object ACE GUIDs":  "01 00 0480 00000000 00000000 00000000 14000000 04 00 1800 0100 0000 05 00 1000 00010000 01000000 00000000",
+	}
+
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeSecurityDescriptor(mustDecodeHex(t, value)); err == nil {
+				t.Errorf("decodeSecurityDescriptor: expected an error")
+			}
+		})
+	}
+}
+
+func TestACLAddACE(t *testing.T) {
+	allow := ace{Type: aceTypeAccessAllowed, Mask: rightReadControl, SID: "S-1-5-11"}
+	inherited := ace{Type: aceTypeAccessAllowed, Flags: aceFlagInherited, Mask: rightFullControl, SID: "S-1-5-18"}
+	deny := ace{Type: aceTypeAccessDenied, Mask: rightDelete | rightDSDeleteTree, SID: sidEveryone}
+	allowObject := ace{Type: aceTypeAccessAllowedObject, Mask: rightDSControlAccess, ObjectType: "00299570-246d-11d0-a768-00aa006e0529", SID: sidBuiltinAdministrators}
+
+	list := &acl{ACEs: []ace{allow, inherited}}
+
+	list.addACE(deny)
+	list.addACE(allowObject)
+	// Equal ignoring the case, not added twice
+	list.addACE(ace{Type: aceTypeAccessAllowedObject, Mask: rightDSControlAccess, ObjectType: "00299570-246D-11D0-A768-00AA006E0529", SID: "s-1-5-32-544"})
+
+	want := []ace{deny, allow, allowObject, inherited}
+	if !reflect.DeepEqual(list.ACEs, want) {
+		t.Fatalf("addACE: got %+v, want %+v", list.ACEs, want)
+	}
+
+	if !list.hasACE(deny) {
+		t.Errorf("hasACE: deny ACE not found")
+	}
+
+	if !list.removeACE(deny) || list.hasACE(deny) {
+		t.Errorf("removeACE: deny ACE not removed")
+	}
+	if list.removeACE(deny) {
+		t.Errorf("removeACE: removed a missing ACE")
+	}
+}
@@ -0,0 +1,66 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Containers of the Sites container of the configuration naming context
+const (
+	sitesSubnetsContainer = "CN=Subnets"
+	sitesIPLinksContainer = "CN=IP,CN=Inter-Site Transports"
+)
+
+// sitesContainerDN returns the DN of the given container of the Sites container of the
+// configuration naming context, or of the Sites container itself if container is empty
+func (c *providerClient) sitesContainerDN(container string) (string, error) {
+	rootDSE, err := c.readRootDSE([]string{"configurationNamingContext"})
+	if err != nil {
+		return "", err
+	}
+
+	configurationDN := rootDSE.GetAttributeValue("configurationNamingContext")
+	if configurationDN == "" {
+		return "", fmt.Errorf("the LDAP server root DSE has no configurationNamingContext")
+	}
+
+	if container == "" {
+		return "CN=Sites," + configurationDN, nil
+	}
+
+	return fmt.Sprintf("%s,CN=Sites,%s", container, configurationDN), nil
+}
+
+// customizeDiffCheckSitesWriteAllowed returns a CustomizeDiffFunc rejecting at plan
+// time any change on an object named `CN=<name>` in the given container of the Sites
+// container, when its DN is forbidden by the provider configuration
+func customizeDiffCheckSitesWriteAllowed(container string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		client, ok := m.(*providerClient)
+		if !ok || client == nil {
+			return nil
+		}
+
+		// Nothing will be written if the plan is empty
+		if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+			return nil
+		}
+
+		if d.Id() != "" && !d.HasChange("name") {
+			return client.checkWriteAllowed(d.Id())
+		}
+
+		if !d.NewValueKnown("name") {
+			return nil
+		}
+
+		containerDN, err := client.sitesContainerDN(container)
+		if err != nil {
+			return err
+		}
+
+		return client.checkWriteAllowed(fmt.Sprintf("CN=%s,%s", d.Get("name").(string), containerDN))
+	}
+}
@@ -0,0 +1,59 @@
+package ldap
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// userAccountControl flags
+const (
+	uacAccountDisable             = 0x00000002
+	uacLockout                    = 0x00000010
+	uacPasswordNotRequired        = 0x00000020
+	uacWorkstationTrustAccount    = 0x00001000
+	uacDontExpirePassword         = 0x00010000
+	uacSmartcardRequired          = 0x00040000
+	uacTrustedForDelegation       = 0x00080000
+	uacNotDelegated               = 0x00100000
+	uacUseDESKeyOnly              = 0x00200000
+	uacDontRequirePreauth         = 0x00400000
+	uacPasswordExpired            = 0x00800000
+	uacTrustedToAuthForDelegation = 0x01000000
+)
+
+// userAccountControlFlags maps the schema attribute exposing a userAccountControl flag to the flag.
+// Lockout and password expiration are read from msDS-User-Account-Control-Computed as they
+// aren't maintained in userAccountControl.
+var userAccountControlFlags = map[string]int{
+	"password_not_required":          uacPasswordNotRequired,
+	"password_never_expires":         uacDontExpirePassword,
+	"smartcard_required":             uacSmartcardRequired,
+	"trusted_for_delegation":         uacTrustedForDelegation,
+	"trusted_to_auth_for_delegation": uacTrustedToAuthForDelegation,
+	"not_delegated":                  uacNotDelegated,
+	"use_des_key_only":               uacUseDESKeyOnly,
+	"dont_require_preauth":           uacDontRequirePreauth,
+}
+
+// setUserAccountControlFlag sets or clears a flag in the userAccountControl of the entry
+func (c *providerClient) setUserAccountControlFlag(dn string, flag int, set bool) error {
+	entry, err := c.readEntry(dn, []string{"userAccountControl"})
+	if err != nil {
+		return err
+	}
+
+	uac, err := strconv.Atoi(entry.GetAttributeValue("userAccountControl"))
+	if err != nil {
+		return fmt.Errorf("invalid userAccountControl of %q: %w", dn, err)
+	}
+
+	newUAC := uac &^ flag
+	if set {
+		newUAC = uac | flag
+	}
+	if newUAC == uac {
+		return nil
+	}
+
+	return c.replaceAttribute(dn, "userAccountControl", []string{strconv.Itoa(newUAC)})
+}
@@ -0,0 +1,86 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// samAccountNameForbiddenChars are the characters Active Directory forbids in a sAMAccountName
+const samAccountNameForbiddenChars = "\"/\\[]:;|=,+*?<>"
+
+// computerNameMaxLength is the maximum length of a computer NetBIOS name, the
+// computer sAMAccountName without its trailing `$`
+const computerNameMaxLength = 15
+
+// validateSAMAccountName validates a sAMAccountName against Active Directory rules:
+// at most 256 characters (20 for pre-Windows 2000 clients), no forbidden or control
+// characters and not ending with a period
+func validateSAMAccountName(v interface{}, path cty.Path) diag.Diagnostics {
+	value := v.(string)
+
+	var diags diag.Diagnostics
+	addError := func(summary string) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			AttributePath: path,
+		})
+	}
+
+	length := len([]rune(value))
+	switch {
+	case length == 0:
+		addError("sAMAccountName can't be empty")
+	case length > 256:
+		addError(fmt.Sprintf("sAMAccountName %q is %d characters long, Active Directory allows at most 256", value, length))
+	case length > 20:
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       fmt.Sprintf("sAMAccountName %q is longer than 20 characters", value),
+			Detail:        "Pre-Windows 2000 clients and some tools only support sAMAccountName values up to 20 characters.",
+			AttributePath: path,
+		})
+	}
+
+	if i := strings.IndexAny(value, samAccountNameForbiddenChars); i >= 0 {
+		addError(fmt.Sprintf("sAMAccountName %q contains the forbidden character %q, the following characters aren't allowed: %s", value, value[i], samAccountNameForbiddenChars))
+	}
+
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			addError(fmt.Sprintf("sAMAccountName %q contains control characters", value))
+			break
+		}
+	}
+
+	if strings.HasSuffix(value, ".") {
+		addError(fmt.Sprintf("sAMAccountName %q can't end with a period", value))
+	}
+
+	if strings.Trim(value, ". ") == "" && length > 0 {
+		addError(fmt.Sprintf("sAMAccountName %q can't contain only periods and spaces", value))
+	}
+
+	return diags
+}
+
+// validateComputerSAMAccountName validates a computer sAMAccountName, whose name
+// without the trailing `$` is the NetBIOS name of the computer
+func validateComputerSAMAccountName(v interface{}, path cty.Path) diag.Diagnostics {
+	value := v.(string)
+
+	if length := len([]rune(strings.TrimSuffix(value, "$"))); length > computerNameMaxLength {
+		return diag.Diagnostics{
+			{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("computer sAMAccountName %q is %d characters long without the trailing $, the NetBIOS name of a computer is at most %d", value, length, computerNameMaxLength),
+				AttributePath: path,
+			},
+		}
+	}
+
+	return validateSAMAccountName(v, path)
+}
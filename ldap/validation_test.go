@@ -0,0 +1,75 @@
+package ldap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestValidateSAMAccountName(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		errors   int
+		warnings int
+	}{
+		{"valid", "svc-web", 0, 0},
+		{"valid with spaces and dots", "Domain Admins.old ", 0, 0},
+		{"20 characters", strings.Repeat("a", 20), 0, 0},
+		{"20 non ASCII characters", strings.Repeat("é", 20), 0, 0},
+		{"21 characters", strings.Repeat("a", 21), 0, 1},
+		{"256 characters", strings.Repeat("a", 256), 0, 1},
+		{"257 characters", strings.Repeat("a", 257), 1, 0},
+		{"empty", "", 1, 0},
+		{"forbidden character", "web/admins", 1, 0},
+		{"forbidden backslash", `DOMAIN\web`, 1, 0},
+		{"control character", "web\tadmins", 1, 0},
+		{"trailing period", "web.", 1, 0},
+		{"only periods and spaces", ". .", 2, 0},
+		{"only spaces", "   ", 1, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := validateSAMAccountName(c.value, cty.GetAttrPath("sam_account_name"))
+
+			errors, warnings := 0, 0
+			for _, d := range diags {
+				switch d.Severity {
+				case diag.Error:
+					errors++
+				case diag.Warning:
+					warnings++
+				}
+				if !d.AttributePath.Equals(cty.GetAttrPath("sam_account_name")) {
+					t.Errorf("diagnostic %q has the attribute path %#v", d.Summary, d.AttributePath)
+				}
+			}
+
+			if errors != c.errors || warnings != c.warnings {
+				t.Errorf("got %d errors and %d warnings, want %d and %d: %+v", errors, warnings, c.errors, c.warnings, diags)
+			}
+		})
+	}
+}
+
+func TestValidateComputerSAMAccountName(t *testing.T) {
+	cases := map[string]bool{
+		"WEB01$":                      true,
+		"web01":                       true,
+		strings.Repeat("A", 15) + "$": true,
+		strings.Repeat("A", 15):       true,
+		strings.Repeat("A", 16) + "$": false,
+		strings.Repeat("A", 16):       false,
+		"WEB/01$":                     false,
+	}
+
+	for value, valid := range cases {
+		diags := validateComputerSAMAccountName(value, cty.GetAttrPath("sam_account_name"))
+		if diags.HasError() == valid {
+			t.Errorf("validateComputerSAMAccountName(%q): got %+v, want valid %t", value, diags, valid)
+		}
+	}
+}